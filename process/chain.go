@@ -13,16 +13,31 @@ func Chain(deps ...Runnable) Runnable {
 	case 1:
 		return deps[0]
 	}
-	return &chainRunnable{deps}
+	return &chainRunnable{deps: deps}
 }
 
 type chainRunnable struct {
 	deps []Runnable
+
+	readiness readiness
 }
 
 func (r *chainRunnable) Run(ctx context.Context, callback Callback) error {
 	s := chainState{deps: r.deps}
-	return s.Run(ctx, callback)
+	return s.Run(ctx, r.readiness.wrap(callback))
+}
+
+// Healthy implements the HealthReporter interface.
+func (r *chainRunnable) Healthy(ctx context.Context) error {
+	if err := r.readiness.err(); err != nil {
+		return err
+	}
+	return healthOfAll(ctx, r.deps)
+}
+
+// children implements the parentRunnable interface.
+func (r *chainRunnable) children() []Runnable {
+	return r.deps
 }
 
 type chainState struct {
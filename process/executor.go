@@ -0,0 +1,71 @@
+package process
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/multierr"
+
+	"go.pact.im/x/task"
+)
+
+// limitedExecutor is a task.Executor that executes tasks in parallel, but runs
+// at most limit tasks concurrently. Tasks are started in the order they are
+// given. Once the execution is canceled, remaining tasks are not started and
+// the context error is returned for them.
+type limitedExecutor struct {
+	limit int
+}
+
+// Execute implements the task.Executor interface.
+func (e *limitedExecutor) Execute(ctx context.Context, cond task.CancelCondition, tasks ...task.Task) error {
+	var once sync.Once
+	var errs []error
+	setError := func(i int, err error) {
+		once.Do(func() { errs = make([]error, len(tasks)) })
+		errs[i] = err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, e.limit)
+
+	var wg sync.WaitGroup
+	for i, t := range tasks {
+		if err := acquire(ctx, sem); err != nil {
+			for j := i; j < len(tasks); j++ {
+				setError(j, err)
+			}
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := t.Run(ctx)
+			if err != nil {
+				setError(i, err)
+			}
+			if cond(err) {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return multierr.Combine(errs...)
+}
+
+// acquire acquires the semaphore unless the context is canceled.
+func acquire(ctx context.Context, sem chan<- struct{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,36 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.pact.im/x/task"
+)
+
+func TestLimitedExecutorCancel(t *testing.T) {
+	oops := errors.New("oops")
+
+	var ran []int
+	tasks := make([]task.Task, 3)
+	for i := range tasks {
+		// Tasks ignore the context so that the executor itself must
+		// not admit them after cancellation.
+		tasks[i] = func(_ context.Context) error {
+			ran = append(ran, i)
+			if i == 0 {
+				return oops
+			}
+			return nil
+		}
+	}
+
+	e := &limitedExecutor{limit: 1}
+	err := e.Execute(context.Background(), task.CancelOnError(), tasks...)
+	if !errors.Is(err, oops) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ran) != 1 {
+		t.Fatalf("expected only the first task to run, got %v", ran)
+	}
+}
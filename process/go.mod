@@ -3,12 +3,14 @@ module go.pact.im/x/process
 go 1.24.0
 
 require (
+	go.pact.im/x/clock v0.0.6
 	go.pact.im/x/task v0.0.6
 	go.uber.org/atomic v1.10.0
+	go.uber.org/multierr v1.9.0
+	go.uber.org/zap v1.24.0
 )
 
 require (
 	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/stretchr/testify v1.9.0 // indirect
-	go.uber.org/multierr v1.9.0 // indirect
 )
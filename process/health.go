@@ -0,0 +1,131 @@
+package process
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/atomic"
+	"go.uber.org/multierr"
+)
+
+// ErrNotReady is an error that is returned from health checks if the process
+// has not reported readiness or is shutting down.
+var ErrNotReady = errors.New("process: not ready")
+
+// RootHealthKey is the key for the health of the whole process tree in the map
+// returned from Health.
+const RootHealthKey = "."
+
+// HealthReporter is an optional interface for Runnable implementations that are
+// able to report their health.
+//
+// Wrappers and combinators provided by this package implement HealthReporter
+// by aggregating the health of underlying processes. Processes that do not
+// implement the interface are considered healthy once they have called the
+// Run’s method callback.
+//
+// Health checks are usually polled by probes and therefore should be cheap.
+type HealthReporter interface {
+	// Healthy returns a non-nil error if the process is not healthy.
+	Healthy(ctx context.Context) error
+}
+
+// Health returns a function that reports health for each named process in the
+// given process tree. The resulting map is keyed by process names, with names
+// of nested processes joined by a slash. Processes are named using the Named
+// function.
+//
+// The health of the whole tree is always reported under the RootHealthKey key,
+// regardless of whether the root process is named. Note that if the root does
+// not implement HealthReporter interface (i.e. it is not a wrapper or
+// combinator from this package), it is always considered healthy.
+//
+// Note that the tree is traversed once on Health invocation. That is, Runnable
+// instances must not be changed after Health is called.
+func Health(tree Runnable) func(ctx context.Context) map[string]error {
+	var named []namedHealth
+	walkNamed(tree, "", &named)
+	return func(ctx context.Context) map[string]error {
+		m := make(map[string]error, len(named)+1)
+		m[RootHealthKey] = healthOf(ctx, tree)
+		for _, n := range named {
+			m[n.path] = n.proc.Healthy(ctx)
+		}
+		return m
+	}
+}
+
+// namedHealth is a named process found in the process tree.
+type namedHealth struct {
+	path string
+	proc *namedRunnable
+}
+
+// parentRunnable is implemented by Runnable wrappers and combinators to allow
+// traversing the process tree.
+type parentRunnable interface {
+	children() []Runnable
+}
+
+// walkNamed appends named processes from the tree with the given root to out.
+func walkNamed(r Runnable, prefix string, out *[]namedHealth) {
+	if p, ok := r.(*namedRunnable); ok {
+		if prefix != "" {
+			prefix += "/"
+		}
+		prefix += p.name
+		*out = append(*out, namedHealth{prefix, p})
+	}
+	if p, ok := r.(parentRunnable); ok {
+		for _, c := range p.children() {
+			walkNamed(c, prefix, out)
+		}
+	}
+}
+
+// healthOf returns the health of the given process. It returns nil if the
+// process does not implement HealthReporter interface.
+func healthOf(ctx context.Context, r Runnable) error {
+	h, ok := r.(HealthReporter)
+	if !ok {
+		return nil
+	}
+	return h.Healthy(ctx)
+}
+
+// healthOfAll returns combined health of the given processes.
+func healthOfAll(ctx context.Context, rs []Runnable) error {
+	var errs []error
+	for _, r := range rs {
+		if err := healthOf(ctx, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return multierr.Combine(errs...)
+}
+
+// readiness tracks whether the process is ready, that is, it has called the
+// callback and the callback has not returned yet. The same Runnable may be run
+// concurrently, so the process is ready while at least one of its callbacks is
+// running.
+type readiness struct {
+	running atomic.Int64
+}
+
+// wrap returns a callback that marks the process as ready while the given
+// callback is running.
+func (r *readiness) wrap(callback Callback) Callback {
+	return func(ctx context.Context) error {
+		r.running.Inc()
+		defer r.running.Dec()
+		return callback(ctx)
+	}
+}
+
+// err returns ErrNotReady if the process is not ready.
+func (r *readiness) err() error {
+	if r.running.Load() <= 0 {
+		return ErrNotReady
+	}
+	return nil
+}
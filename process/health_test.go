@@ -0,0 +1,177 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// healthTestRunnable is a Runnable that reports the given health error.
+type healthTestRunnable struct {
+	err error
+}
+
+func (r *healthTestRunnable) Run(ctx context.Context, callback Callback) error {
+	return callback(ctx)
+}
+
+func (r *healthTestRunnable) Healthy(_ context.Context) error {
+	return r.err
+}
+
+func TestHealth(t *testing.T) {
+	oops := errors.New("oops")
+
+	tree := Named("app", Parallel(
+		Named("db", &healthTestRunnable{err: oops}, nil),
+		Named("http", Restart(Nop(), RestartPolicy{}), nil),
+	), nil)
+	health := Health(tree)
+
+	ctx := context.Background()
+	for name, err := range health(ctx) {
+		if !errors.Is(err, ErrNotReady) {
+			t.Fatalf("%s: expected not ready error before Run, got %v", name, err)
+		}
+	}
+
+	err := tree.Run(ctx, func(ctx context.Context) error {
+		m := health(ctx)
+		if len(m) != 4 {
+			t.Fatalf("unexpected health map: %v", m)
+		}
+		if err := m[RootHealthKey]; !errors.Is(err, oops) {
+			t.Errorf("unexpected root health: %v", err)
+		}
+		if err := m["app/db"]; !errors.Is(err, oops) || err.Error() != "db: oops" {
+			t.Errorf("unexpected db health: %v", err)
+		}
+		if err := m["app/http"]; err != nil {
+			t.Errorf("unexpected http health: %v", err)
+		}
+		if err := m["app"]; !errors.Is(err, oops) {
+			t.Errorf("unexpected app health: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, err := range health(ctx) {
+		if !errors.Is(err, ErrNotReady) {
+			t.Fatalf("%s: expected not ready error after Run, got %v", name, err)
+		}
+	}
+}
+
+func TestHealthUnnamed(t *testing.T) {
+	tree := Parallel(Nop(), Nop())
+	health := Health(tree)
+
+	ctx := context.Background()
+	if err := health(ctx)[RootHealthKey]; !errors.Is(err, ErrNotReady) {
+		t.Fatalf("expected not ready error before Run, got %v", err)
+	}
+
+	err := tree.Run(ctx, func(ctx context.Context) error {
+		m := health(ctx)
+		if len(m) != 1 {
+			t.Fatalf("unexpected health map: %v", m)
+		}
+		if err := m[RootHealthKey]; err != nil {
+			t.Errorf("unexpected root health: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := health(ctx)[RootHealthKey]; !errors.Is(err, ErrNotReady) {
+		t.Fatalf("expected not ready error after Run, got %v", err)
+	}
+}
+
+func TestHealthContinueOnErrorFailed(t *testing.T) {
+	oops := errors.New("oops")
+
+	tree := ParallelWithOptions(ParallelOptions{
+		Policy: ContinueOnError,
+	}, failingRunnable(oops, nil), Nop())
+	health := Health(tree)
+
+	err := tree.Run(context.Background(), func(ctx context.Context) error {
+		if err := health(ctx)[RootHealthKey]; !errors.Is(err, oops) {
+			t.Errorf("unexpected root health: %v", err)
+		}
+		return nil
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestHealthConcurrentRuns(t *testing.T) {
+	oops := errors.New("oops")
+
+	tree := ParallelWithOptions(ParallelOptions{
+		Policy: ContinueOnError,
+	}, failingRunnable(oops, nil), Nop())
+	health := Health(tree)
+
+	ctx := context.Background()
+	ready := make(chan struct{})
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- tree.Run(ctx, func(_ context.Context) error {
+			close(ready)
+			<-stop
+			return nil
+		})
+	}()
+	<-ready
+
+	err := tree.Run(ctx, func(_ context.Context) error {
+		return nil
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The first run is still active and reports its failed dependency.
+	if err := health(ctx)[RootHealthKey]; !errors.Is(err, oops) {
+		t.Errorf("unexpected root health: %v", err)
+	}
+
+	close(stop)
+	if err := <-done; !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := health(ctx)[RootHealthKey]; !errors.Is(err, ErrNotReady) {
+		t.Fatalf("expected not ready error after Run, got %v", err)
+	}
+}
+
+func TestHealthSupervisor(t *testing.T) {
+	s := NewSupervisor([]Child{
+		{Name: "a", Runnable: Nop()},
+		{Name: "b", Runnable: Nop()},
+	}, SupervisorOptions{})
+	health := Health(s)
+
+	err := s.Run(context.Background(), func(ctx context.Context) error {
+		m := health(ctx)
+		if len(m) != 3 || m[RootHealthKey] != nil || m["a"] != nil || m["b"] != nil {
+			t.Errorf("unexpected health map: %v", m)
+		}
+		if err := s.Healthy(ctx); err != nil {
+			t.Errorf("unexpected supervisor health: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"go.uber.org/atomic"
+)
+
+// PanicError is an error that is returned from Run if the process panics.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("process: panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// catchPanicsRunnable is a Runnable that recovers panics in the underlying
+// process.
+type catchPanicsRunnable struct {
+	proc Runnable
+}
+
+// CatchPanics returns a Runnable instance that recovers panics from the Run
+// method of the given process and returns them as a PanicError. This allows
+// process groups to handle panics according to their error policy, e.g. shut
+// down other processes gracefully.
+//
+// Panics from the callback are not recovered and propagate to the caller. Note
+// that panics in goroutines started by the process cannot be recovered.
+func CatchPanics(p Runnable) Runnable {
+	return &catchPanicsRunnable{proc: p}
+}
+
+// Run implements the Runnable interface.
+func (r *catchPanicsRunnable) Run(ctx context.Context, callback Callback) (err error) {
+	var callbackPanicked atomic.Bool
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		if callbackPanicked.Load() {
+			panic(v)
+		}
+		err = &PanicError{
+			Value: v,
+			Stack: debug.Stack(),
+		}
+	}()
+	return r.proc.Run(ctx, func(ctx context.Context) error {
+		panicking := true
+		defer func() {
+			if panicking {
+				callbackPanicked.Store(true)
+			}
+		}()
+		err := callback(ctx)
+		panicking = false
+		return err
+	})
+}
+
+// Healthy implements the HealthReporter interface.
+func (r *catchPanicsRunnable) Healthy(ctx context.Context) error {
+	return healthOf(ctx, r.proc)
+}
+
+// children implements the parentRunnable interface.
+func (r *catchPanicsRunnable) children() []Runnable {
+	return []Runnable{r.proc}
+}
@@ -0,0 +1,66 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCatchPanics(t *testing.T) {
+	oops := errors.New("oops")
+	fail := make(chan struct{})
+
+	var stopped bool
+	par := Parallel(
+		RunnableFunc(func(ctx context.Context, callback Callback) error {
+			err := callback(ctx)
+			stopped = true
+			return err
+		}),
+		CatchPanics(RunnableFunc(func(ctx context.Context, callback Callback) error {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			go func() {
+				select {
+				case <-ctx.Done():
+				case <-fail:
+					cancel()
+				}
+			}()
+			_ = callback(ctx)
+			panic(oops)
+		})),
+	)
+
+	err := par.Run(context.Background(), func(ctx context.Context) error {
+		close(fail)
+		<-ctx.Done()
+		return nil
+	})
+
+	var panicError *PanicError
+	if !errors.As(err, &panicError) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(err, oops) {
+		t.Fatalf("expected error to wrap panic value: %v", err)
+	}
+	if len(panicError.Stack) == 0 {
+		t.Fatal("expected stack trace")
+	}
+	if !stopped {
+		t.Fatal("expected sibling process to be stopped")
+	}
+}
+
+func TestCatchPanicsCallback(t *testing.T) {
+	defer func() {
+		if v := recover(); v != "oops" {
+			t.Fatalf("unexpected panic value: %v", v)
+		}
+	}()
+	_ = CatchPanics(Nop()).Run(context.Background(), func(_ context.Context) error {
+		panic("oops")
+	})
+	t.Fatal("expected panic")
+}
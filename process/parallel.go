@@ -2,19 +2,31 @@ package process
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"sync"
 
+	"go.uber.org/atomic"
+	"go.uber.org/multierr"
+
 	"go.pact.im/x/task"
 )
 
+// ErrNoDependencyStarted is an error that is returned if none of the
+// dependencies has started and their errors were ignored.
+var ErrNoDependencyStarted = errors.New("process: no dependency started")
+
 // Parallel returns a Runnable instance that starts and runs processes in
 // parallel. If no processes are given, it returns Nop instance.
 //
 // The resulting Runnable calls callback after all process dependencies are
 // successfully started. If any dependecy fails to start, processes that have
-// already started are gracefully stopped. If any dependency fails before the
-// main callback returns, the context passed to callback is canceled and all
-// processes are gracefully stopped (unless the parent context has expired).
+// already started are gracefully stopped. If a dependency that has already
+// started terminates while other dependencies are still starting, their
+// startup is canceled and Run returns without invoking callback. If any
+// dependency fails before the main callback returns, the context passed to
+// callback is canceled and all processes are gracefully stopped (unless the
+// parent context has expired).
 //
 // The callbacks of dependencies return after the callback of the resulting
 // dependent process. Run returns callback error if it is not nil, otherwise it
@@ -32,8 +44,87 @@ func Parallel(deps ...Runnable) Runnable {
 	}
 }
 
+// ErrorPolicy defines how a group of processes handles dependency failures.
+type ErrorPolicy int
+
+const (
+	// FailFast policy stops all processes when any dependency fails or
+	// terminates. The callback is invoked after all dependencies have
+	// started successfully. This is the default policy of Parallel.
+	FailFast ErrorPolicy = iota
+	// ContinueOnError policy keeps running remaining dependencies when
+	// some of them fail. The callback is invoked after every dependency
+	// has either started or failed, provided that at least one dependency
+	// has started successfully. The context passed to callback is canceled
+	// once all dependencies terminate. Errors are returned from Run after
+	// the callback returns.
+	ContinueOnError
+)
+
+// ParallelOptions is a set of options for ParallelWithOptions function.
+type ParallelOptions struct {
+	// Policy is the error policy. Defaults to FailFast.
+	Policy ErrorPolicy
+
+	// IgnoreError is an optional predicate that reports whether an error
+	// returned by the dependency with the given index should be ignored.
+	// Ignored errors are not returned from Run and do not cause shutdown
+	// of other processes.
+	//
+	// Note that if IgnoreError is set, a terminating dependency does not
+	// wait for the callback to return. If every dependency fails to start
+	// with an ignored error, Run returns ErrNoDependencyStarted without
+	// invoking callback.
+	IgnoreError func(i int, err error) bool
+
+	// Limit is the maximum number of dependencies that are starting
+	// concurrently. Dependencies are started in the order they are given,
+	// and a dependency is started once the previous one reports readiness
+	// if the limit is reached. Zero or negative value means no limit.
+	//
+	// Note that the limit applies only to the startup phase, i.e. all
+	// dependencies run and shut down concurrently once started.
+	Limit int
+}
+
+// ParallelWithOptions returns a Runnable instance that starts and runs
+// processes in parallel using the given options. See Parallel and ErrorPolicy
+// documentation for more details.
+func ParallelWithOptions(o ParallelOptions, deps ...Runnable) Runnable {
+	limited := o.Limit > 0 && o.Limit < len(deps)
+	if o.Policy == FailFast && o.IgnoreError == nil && !limited {
+		return Parallel(deps...)
+	}
+	if len(deps) == 0 {
+		return Nop()
+	}
+	r := &groupRunnable{
+		deps:   deps,
+		exec:   task.ParallelExecutor(),
+		policy: o.Policy,
+		ignore: o.IgnoreError,
+	}
+	if limited {
+		r.startExec = &limitedExecutor{limit: o.Limit}
+	}
+	return r
+}
+
+// ParallelN returns a Runnable instance that starts and runs processes in
+// parallel, but starts at most limit processes concurrently. It is a shorthand
+// for ParallelWithOptions with the given Limit option.
+func ParallelN(limit int, deps ...Runnable) Runnable {
+	return ParallelWithOptions(ParallelOptions{Limit: limit}, deps...)
+}
+
 // Sequential returns a Runnable instance with the same guarantees as the
-// Parallel function, but starts and stops processes in sequential order.
+// Parallel function, but starts processes in sequential order and stops them
+// in the reverse order.
+//
+// The next process is started only after the previous one calls its callback,
+// i.e. reports that it is ready. If any process fails to start, processes that
+// have not been started yet are skipped. Dependency errors are combined in the
+// shutdown order.
 func Sequential(deps ...Runnable) Runnable {
 	switch len(deps) {
 	case 0:
@@ -42,17 +133,44 @@ func Sequential(deps ...Runnable) Runnable {
 		return deps[0]
 	}
 	return &groupRunnable{
-		deps: deps,
-		exec: task.SequentialExecutor(),
+		deps:    deps,
+		exec:    task.SequentialExecutor(),
+		reverse: true,
 	}
 }
 
 type groupRunnable struct {
 	deps []Runnable
 	exec task.Executor
+
+	// startExec is an optional executor for the startup phase. If it is
+	// nil, exec is used instead.
+	startExec task.Executor
+
+	// reverse indicates that processes should be stopped in the reverse
+	// order.
+	reverse bool
+
+	// policy is the error policy for dependencies.
+	policy ErrorPolicy
+
+	// ignore is an optional predicate for ignored dependency errors.
+	ignore func(i int, err error) bool
+
+	// mu guards runs.
+	mu sync.Mutex
+	// runs are dependency processes of active runWithPolicy invocations.
+	// It is used to report failed dependencies in Healthy.
+	runs map[*[]*Process]struct{}
+
+	readiness readiness
 }
 
 func (r *groupRunnable) Run(ctx context.Context, callback Callback) error {
+	callback = r.readiness.wrap(callback)
+	if r.policy != FailFast || r.ignore != nil {
+		return r.runWithPolicy(ctx, callback)
+	}
 	var once sync.Once
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -82,6 +200,13 @@ func (r *groupRunnable) Run(ctx context.Context, callback Callback) error {
 		p := NewProcess(ctx, Chain(dep, RunnableFunc(child)))
 		procs[i] = p
 		startTasks[i] = func(ctx context.Context) error {
+			// Do not start the process if startup was canceled.
+			// Stop would then prevent the process from starting.
+			if err := ctx.Err(); err != nil {
+				once.Do(wg.Done)
+				return err
+			}
+
 			err := p.Start(ctx)
 			if err == nil {
 				return nil
@@ -102,17 +227,24 @@ func (r *groupRunnable) Run(ctx context.Context, callback Callback) error {
 		}
 	}
 
-	startError := r.exec.Execute(ctx, task.CancelOnError(), startTasks...)
+	if r.reverse {
+		slices.Reverse(stopTasks)
+	}
+
+	// Note that we use fgctx for startup so that a failure of the process
+	// that has already started cancels startup of the remaining ones.
+	startError := r.startExecutor().Execute(fgctx, task.CancelOnError(), startTasks...)
 
 	var callbackError error
 	if startError == nil {
 		callbackError = callback(fgctx)
-
-		// Main callback has returned, unblock callbacks for
-		// dependencies.
-		wg.Done()
 	}
 
+	// Either main callback has returned or startup has failed, unblock
+	// callbacks for dependencies. Note that the executor may skip start
+	// tasks that would otherwise do this on failure.
+	once.Do(wg.Done)
+
 	stopError := r.exec.Execute(ctx, task.NeverCancel(), stopTasks...)
 
 	if callbackError != nil {
@@ -121,3 +253,185 @@ func (r *groupRunnable) Run(ctx context.Context, callback Callback) error {
 
 	return stopError
 }
+
+// runWithPolicy is an implementation of the Run method that supports error
+// policies. Unlike Run, dependencies do not wait for the main callback to
+// return when they terminate.
+func (r *groupRunnable) runWithPolicy(ctx context.Context, callback Callback) error {
+	// fgctx is passed to callback and cancel is used to cancel callback
+	// invocation when the dependencies terminate.
+	fgctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	n := len(r.deps)
+
+	// live is the number of dependencies that are either starting or
+	// running, plus one for the startup phase.
+	var live atomic.Int64
+	live.Store(int64(n) + 1)
+	done := func() {
+		if live.Dec() == 0 {
+			cancel()
+		}
+	}
+
+	var started atomic.Bool
+	var wg sync.WaitGroup
+
+	procs := make([]*Process, n)
+
+	tasksArena := make([]task.Task, 2*n)
+	startTasks := tasksArena[0*n : 1*n]
+	stopTasks := tasksArena[1*n : 2*n]
+	for i, dep := range r.deps {
+		p := NewProcess(ctx, dep)
+		procs[i] = p
+		startTasks[i] = func(ctx context.Context) error {
+			if err := ctx.Err(); err != nil {
+				done()
+				return err
+			}
+
+			if err := p.Start(ctx); err != nil {
+				done()
+				if err := p.Err(); err != nil && r.ignored(i, err) {
+					return nil
+				}
+				if r.policy == ContinueOnError && ctx.Err() == nil {
+					return nil
+				}
+				return err
+			}
+			started.Store(true)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-p.Done()
+				// Note that with FailFast policy, a dependency
+				// that terminates without error still causes
+				// shutdown of other processes.
+				if err := p.Err(); r.policy == FailFast && (err == nil || !r.ignored(i, err)) {
+					cancel()
+				}
+				done()
+			}()
+			return nil
+		}
+		stopTasks[i] = func(ctx context.Context) error {
+			// We get either ErrProcessInvalidState or p.Err
+			// from Stop so it is safe to ignore error here.
+			_ = p.Stop(ctx)
+			if err := p.Err(); err != nil && !r.ignored(i, err) {
+				return err
+			}
+			return nil
+		}
+	}
+
+	r.addRun(&procs)
+	defer r.removeRun(&procs)
+
+	startError := r.startExecutor().Execute(fgctx, task.CancelOnError(), startTasks...)
+	done()
+
+	var callbackError error
+	if startError == nil && started.Load() {
+		callbackError = callback(fgctx)
+	}
+
+	stopError := r.exec.Execute(ctx, task.NeverCancel(), stopTasks...)
+	wg.Wait()
+
+	if callbackError != nil {
+		return callbackError
+	}
+	if stopError == nil && startError == nil && !started.Load() {
+		return ErrNoDependencyStarted
+	}
+	return stopError
+}
+
+// Healthy implements the HealthReporter interface. Dependencies that have
+// failed (e.g. with ContinueOnError policy) are reported with their errors.
+func (r *groupRunnable) Healthy(ctx context.Context) error {
+	if err := r.readiness.err(); err != nil {
+		return err
+	}
+	runs := r.activeRuns()
+	if len(runs) == 0 {
+		return healthOfAll(ctx, r.deps)
+	}
+	var errs []error
+	for i, dep := range r.deps {
+		if err := r.failed(runs, i); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := healthOf(ctx, dep); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return multierr.Combine(errs...)
+}
+
+// failed returns the error of the dependency with the given index if it has
+// failed in any of the given runs and the error is not ignored.
+func (r *groupRunnable) failed(runs [][]*Process, i int) error {
+	for _, procs := range runs {
+		if err := procs[i].Err(); err != nil && !r.ignored(i, err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// activeRuns returns dependency processes of active runWithPolicy invocations.
+func (r *groupRunnable) activeRuns() [][]*Process {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	runs := make([][]*Process, 0, len(r.runs))
+	for procs := range r.runs {
+		runs = append(runs, *procs)
+	}
+	return runs
+}
+
+// addRun registers dependency processes of the runWithPolicy invocation.
+func (r *groupRunnable) addRun(procs *[]*Process) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.runs == nil {
+		r.runs = make(map[*[]*Process]struct{})
+	}
+	r.runs[procs] = struct{}{}
+}
+
+// removeRun unregisters dependency processes of the runWithPolicy invocation.
+func (r *groupRunnable) removeRun(procs *[]*Process) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.runs, procs)
+}
+
+// children implements the parentRunnable interface.
+func (r *groupRunnable) children() []Runnable {
+	return r.deps
+}
+
+// startExecutor returns the executor for the startup phase.
+func (r *groupRunnable) startExecutor() task.Executor {
+	if r.startExec != nil {
+		return r.startExec
+	}
+	return r.exec
+}
+
+// ignored returns true if the error returned by dependency with the given
+// index should be ignored.
+func (r *groupRunnable) ignored(i int, err error) bool {
+	if err == nil {
+		return true
+	}
+	return r.ignore != nil && r.ignore(i, err)
+}
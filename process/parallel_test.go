@@ -2,7 +2,11 @@ package process
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestParallel(t *testing.T) {
@@ -44,3 +48,348 @@ func testParallel(t *testing.T, count int) {
 		}
 	}
 }
+
+func TestSequentialOrder(t *testing.T) {
+	const count = 3
+
+	var events []string
+	deps := make([]Runnable, count)
+	for i := range deps {
+		deps[i] = RunnableFunc(func(ctx context.Context, callback Callback) error {
+			events = append(events, fmt.Sprintf("start %d", i))
+			err := callback(ctx)
+			events = append(events, fmt.Sprintf("stop %d", i))
+			return err
+		})
+	}
+
+	seq := Sequential(deps...)
+	err := seq.Run(context.Background(), func(_ context.Context) error {
+		events = append(events, "callback")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"start 0",
+		"start 1",
+		"start 2",
+		"callback",
+		"stop 2",
+		"stop 1",
+		"stop 0",
+	}
+	if !slices.Equal(expected, events) {
+		t.Fatalf("unexpected events: %q", events)
+	}
+}
+
+func TestSequentialFailsBeforeReady(t *testing.T) {
+	oops := errors.New("oops")
+
+	var started, stopped, third bool
+	seq := Sequential(
+		RunnableFunc(func(ctx context.Context, callback Callback) error {
+			started = true
+			err := callback(ctx)
+			stopped = true
+			return err
+		}),
+		RunnableFunc(func(_ context.Context, _ Callback) error {
+			return oops
+		}),
+		RunnableFunc(func(ctx context.Context, callback Callback) error {
+			third = true
+			return callback(ctx)
+		}),
+	)
+
+	err := seq.Run(context.Background(), func(_ context.Context) error {
+		t.Error("unexpected callback invocation")
+		return nil
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !started || !stopped {
+		t.Fatal("expected started process to be stopped")
+	}
+	if third {
+		t.Fatal("expected remaining process to be skipped")
+	}
+}
+
+func TestSequentialFailsAfterReady(t *testing.T) {
+	oops := errors.New("oops")
+	fail := make(chan struct{})
+
+	seq := Sequential(
+		RunnableFunc(func(ctx context.Context, callback Callback) error {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			go func() {
+				select {
+				case <-ctx.Done():
+				case <-fail:
+					cancel()
+				}
+			}()
+			_ = callback(ctx)
+			return oops
+		}),
+		Nop(),
+	)
+
+	err := seq.Run(context.Background(), func(ctx context.Context) error {
+		close(fail)
+		<-ctx.Done()
+		return nil
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// failingRunnable returns a Runnable that reports readiness and fails with the
+// given error once fail channel is closed. It returns err before readiness if
+// fail is nil.
+func failingRunnable(err error, fail <-chan struct{}) Runnable {
+	return RunnableFunc(func(ctx context.Context, callback Callback) error {
+		if fail == nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			select {
+			case <-ctx.Done():
+			case <-fail:
+				cancel()
+			}
+		}()
+		_ = callback(ctx)
+		select {
+		case <-fail:
+			return err
+		default:
+		}
+		return nil
+	})
+}
+
+func TestParallelContinueOnErrorBeforeReady(t *testing.T) {
+	oops := errors.New("oops")
+
+	var running bool
+	par := ParallelWithOptions(ParallelOptions{
+		Policy: ContinueOnError,
+	}, failingRunnable(oops, nil), RunnableFunc(func(ctx context.Context, callback Callback) error {
+		running = true
+		return callback(ctx)
+	}))
+
+	err := par.Run(context.Background(), func(ctx context.Context) error {
+		if ctx.Err() != nil {
+			t.Error("unexpected context cancellation")
+		}
+		return nil
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !running {
+		t.Fatal("expected healthy process to run")
+	}
+}
+
+func TestParallelContinueOnErrorAfterReady(t *testing.T) {
+	oops := errors.New("oops")
+	fail := make(chan struct{})
+	failed := make(chan struct{})
+
+	par := ParallelWithOptions(ParallelOptions{
+		Policy: ContinueOnError,
+	}, Chain(failingRunnable(oops, fail), RunnableFunc(func(ctx context.Context, callback Callback) error {
+		err := callback(ctx)
+		close(failed)
+		return err
+	})), Nop())
+
+	err := par.Run(context.Background(), func(ctx context.Context) error {
+		close(fail)
+		<-failed
+		if ctx.Err() != nil {
+			t.Error("unexpected context cancellation")
+		}
+		return nil
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParallelContinueOnErrorAllFailed(t *testing.T) {
+	oops := errors.New("oops")
+
+	par := ParallelWithOptions(ParallelOptions{
+		Policy: ContinueOnError,
+	}, failingRunnable(oops, nil), failingRunnable(oops, nil))
+
+	err := par.Run(context.Background(), func(_ context.Context) error {
+		t.Error("unexpected callback invocation")
+		return nil
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParallelIgnoreErrorAllFailed(t *testing.T) {
+	oops := errors.New("oops")
+
+	par := ParallelWithOptions(ParallelOptions{
+		IgnoreError: func(_ int, _ error) bool {
+			return true
+		},
+	}, failingRunnable(oops, nil), failingRunnable(oops, nil))
+
+	err := par.Run(context.Background(), func(_ context.Context) error {
+		t.Error("unexpected callback invocation")
+		return nil
+	})
+	if !errors.Is(err, ErrNoDependencyStarted) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParallelFailFast(t *testing.T) {
+	oops := errors.New("oops")
+	fail := make(chan struct{})
+
+	par := ParallelWithOptions(ParallelOptions{
+		IgnoreError: func(_ int, err error) bool {
+			return errors.Is(err, context.Canceled)
+		},
+	}, failingRunnable(oops, fail), Nop())
+
+	err := par.Run(context.Background(), func(ctx context.Context) error {
+		close(fail)
+		<-ctx.Done()
+		return nil
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParallelIgnoreError(t *testing.T) {
+	oops := errors.New("oops")
+	fail := make(chan struct{})
+	failed := make(chan struct{})
+
+	par := ParallelWithOptions(ParallelOptions{
+		IgnoreError: func(i int, err error) bool {
+			return i == 0 && errors.Is(err, oops)
+		},
+	}, Chain(failingRunnable(oops, fail), RunnableFunc(func(ctx context.Context, callback Callback) error {
+		err := callback(ctx)
+		close(failed)
+		return err
+	})), Nop())
+
+	err := par.Run(context.Background(), func(ctx context.Context) error {
+		close(fail)
+		<-failed
+		if ctx.Err() != nil {
+			t.Error("unexpected context cancellation")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParallelN(t *testing.T) {
+	c, _, fakeClock := newRestartTestClock()
+	start := fakeClock.Now()
+
+	const count = 5
+	const limit = 2
+
+	starting := make(chan int, count)
+	startedAt := make([]time.Time, count)
+
+	deps := make([]Runnable, count)
+	for i := range deps {
+		deps[i] = RunnableFunc(func(ctx context.Context, callback Callback) error {
+			startedAt[i] = c.Now()
+			timer := c.Timer(time.Second)
+			defer timer.Stop()
+			starting <- i
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C():
+			}
+			return callback(ctx)
+		})
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- ParallelN(limit, deps...).Run(context.Background(), func(_ context.Context) error {
+			return nil
+		})
+	}()
+
+	for batch := 0; batch < count; batch += limit {
+		var indices []int
+		for i := batch; i < min(batch+limit, count); i++ {
+			indices = append(indices, <-starting)
+		}
+		slices.Sort(indices)
+		for k, i := range indices {
+			if i != batch+k {
+				t.Fatalf("unexpected startup order: %v", indices)
+			}
+		}
+		fakeClock.Add(time.Second)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, at := range startedAt {
+		expected := start.Add(time.Duration(i/limit) * time.Second)
+		if !at.Equal(expected) {
+			t.Fatalf("process %d started at %v, expected %v", i, at, expected)
+		}
+	}
+}
+
+func TestParallelTerminatesDuringStartup(t *testing.T) {
+	oops := errors.New("oops")
+	fail := make(chan struct{})
+	close(fail)
+
+	par := Parallel(
+		failingRunnable(oops, fail),
+		RunnableFunc(func(ctx context.Context, _ Callback) error {
+			// Never becomes ready.
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+
+	err := par.Run(context.Background(), func(_ context.Context) error {
+		t.Fatal("unexpected callback invocation")
+		return nil
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"go.pact.im/x/clock"
+)
+
+// ErrRestartLimit is an error that is returned if the process has been
+// restarted too many times within the configured time window.
+var ErrRestartLimit = errors.New("process: restart limit exceeded")
+
+const (
+	defaultRestartMinBackoff = 100 * time.Millisecond
+	defaultRestartMaxBackoff = 30 * time.Second
+	defaultRestartWindow     = time.Minute
+)
+
+// RestartPolicy is a set of options for the Restart function.
+type RestartPolicy struct {
+	// Clock is the clock to use. Defaults to system clock.
+	Clock *clock.Clock
+
+	// MinBackoff is the delay before the first restart. The delay is
+	// doubled on each consecutive restart until it reaches MaxBackoff.
+	// Defaults to 100 milliseconds.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between restarts. If the process
+	// has been running for at least MaxBackoff after reporting readiness,
+	// the delay is reset to MinBackoff. Defaults to 30 seconds.
+	MaxBackoff time.Duration
+
+	// Jitter returns the actual delay for the given backoff delay.
+	// Defaults to a random duration in [d/2, d) interval.
+	Jitter func(d time.Duration) time.Duration
+
+	// MaxRestarts is the maximum number of restarts within the Window
+	// time interval. If the limit is exceeded, Run returns an error that
+	// wraps ErrRestartLimit and the last process error. Zero value means
+	// no limit.
+	MaxRestarts int
+
+	// Window is the time interval for MaxRestarts limit. Defaults to one
+	// minute.
+	Window time.Duration
+
+	// Permanent reports whether the given error is permanent and should be
+	// returned without restarting the process. Defaults to a function that
+	// always returns false.
+	Permanent func(err error) bool
+}
+
+// setDefaults sets default values for unspecified options.
+func (p *RestartPolicy) setDefaults() {
+	if p.Clock == nil {
+		p.Clock = clock.System()
+	}
+	if p.MinBackoff <= 0 {
+		p.MinBackoff = defaultRestartMinBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultRestartMaxBackoff
+	}
+	if p.MaxBackoff < p.MinBackoff {
+		p.MaxBackoff = p.MinBackoff
+	}
+	if p.Jitter == nil {
+		p.Jitter = restartJitter
+	}
+	if p.Window <= 0 {
+		p.Window = defaultRestartWindow
+	}
+	if p.Permanent == nil {
+		p.Permanent = func(_ error) bool { return false }
+	}
+}
+
+// backoff returns the delay before the nth consecutive restart.
+func (p *RestartPolicy) backoff(n uint) time.Duration {
+	d := p.MinBackoff
+	for ; n > 0; n-- {
+		if d >= p.MaxBackoff/2 {
+			return p.MaxBackoff
+		}
+		d *= 2
+	}
+	return d
+}
+
+// restartJitter is the default jitter function for RestartPolicy.
+func restartJitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d/2 + rand.N(d-d/2)
+}
+
+// Restart returns a Runnable instance that runs the given process and restarts
+// it when Run returns a non-nil error.
+//
+// The resulting Runnable calls callback once the process reports readiness for
+// the first time. Subsequent restarts do not affect the callback invocation,
+// that is, the context passed to callback is canceled only if the process is
+// not restarted (e.g. it has returned a permanent error or the restart limit
+// was exceeded).
+//
+// The process is not restarted if it returns a nil error, the context expires
+// or the callback has returned. Run returns callback error if it is not nil,
+// otherwise it returns the last process error. If the callback returns while
+// the process is failing or waiting to be restarted, the (non-permanent) error
+// is discarded since shutdown was requested.
+func Restart(r Runnable, policy RestartPolicy) Runnable {
+	policy.setDefaults()
+	return &restartRunnable{
+		proc:   r,
+		policy: policy,
+	}
+}
+
+type restartRunnable struct {
+	proc   Runnable
+	policy RestartPolicy
+
+	// onRestart is an optional function that is called with the process
+	// error and the delay before restarting the process.
+	onRestart func(err error, d time.Duration)
+
+	// readiness tracks readiness of the current process run.
+	readiness readiness
+}
+
+// Healthy implements the HealthReporter interface. The process is not healthy
+// while it is being restarted.
+func (r *restartRunnable) Healthy(ctx context.Context) error {
+	if err := r.readiness.err(); err != nil {
+		return err
+	}
+	return healthOf(ctx, r.proc)
+}
+
+// children implements the parentRunnable interface.
+func (r *restartRunnable) children() []Runnable {
+	return []Runnable{r.proc}
+}
+
+func (r *restartRunnable) Run(ctx context.Context, callback Callback) error {
+	// fgctx is passed to callback and cancel is used to cancel callback
+	// invocation after the process stops without restart.
+	fgctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s := restartState{
+		proc:      r.proc,
+		policy:    &r.policy,
+		onRestart: r.onRestart,
+		readiness: &r.readiness,
+		ready:     make(chan struct{}),
+		stop:      make(chan struct{}),
+	}
+
+	var runError error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runError = s.run(ctx)
+		cancel()
+	}()
+
+	select {
+	case <-s.ready:
+	case <-done:
+		return runError
+	}
+
+	callbackError := callback(fgctx)
+
+	// Main callback has returned, stop the process.
+	close(s.stop)
+	<-done
+
+	if callbackError != nil {
+		return callbackError
+	}
+	return runError
+}
+
+// restartState is the state of the Restart’s Run method invocation.
+type restartState struct {
+	proc      Runnable
+	policy    *RestartPolicy
+	onRestart func(err error, d time.Duration)
+	readiness *readiness
+
+	// ready is closed when the process reports readiness for the first
+	// time.
+	ready chan struct{}
+	once  sync.Once
+
+	// stop is closed when the main callback returns.
+	stop chan struct{}
+}
+
+// attempt runs the process once. It returns the time when the process has
+// reported readiness (zero if it has not), whether the process was stopped
+// gracefully after the main callback has returned, and the process error.
+//
+// If the main callback returns before the process reports readiness (e.g. the
+// process is being restarted), the context of the attempt is canceled to
+// shut down the process instead of waiting for readiness.
+func (s *restartState) attempt(ctx context.Context) (readyAt time.Time, stopped bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ready := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-s.stop:
+			select {
+			case <-ready:
+			default:
+				cancel()
+			}
+		case <-ready:
+		case <-done:
+		}
+	}()
+
+	var readyOnce sync.Once
+	err = s.proc.Run(ctx, func(ctx context.Context) error {
+		readyOnce.Do(func() {
+			readyAt = s.policy.Clock.Now()
+			close(ready)
+		})
+		s.once.Do(func() { close(s.ready) })
+		return s.readiness.wrap(func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+			case <-s.stop:
+				stopped = true
+			}
+			return nil
+		})(ctx)
+	})
+	return readyAt, stopped, err
+}
+
+// run runs the process and restarts it on failure.
+func (s *restartState) run(ctx context.Context) error {
+	c := s.policy.Clock
+
+	var n uint
+	var restarts []time.Time
+	for {
+		readyAt, stopped, err := s.attempt(ctx)
+		if err == nil || ctx.Err() != nil || s.policy.Permanent(err) {
+			return err
+		}
+		select {
+		case <-s.stop:
+			// Report errors from graceful shutdown, but discard
+			// transient errors from the process that has failed
+			// while the main callback was returning.
+			if stopped {
+				return err
+			}
+			return nil
+		default:
+		}
+
+		now := c.Now()
+		if !readyAt.IsZero() && now.Sub(readyAt) >= s.policy.MaxBackoff {
+			n = 0
+		}
+
+		if s.policy.MaxRestarts > 0 {
+			restarts = slices.DeleteFunc(restarts, func(t time.Time) bool {
+				return now.Sub(t) >= s.policy.Window
+			})
+			if len(restarts) >= s.policy.MaxRestarts {
+				return fmt.Errorf("%w: %w", ErrRestartLimit, err)
+			}
+			restarts = append(restarts, now)
+		}
+
+		d := s.policy.Jitter(s.policy.backoff(n))
+		n++
+
+		if s.onRestart != nil {
+			s.onRestart(err, d)
+		}
+
+		timer := c.Timer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-s.stop:
+			// Shutdown was requested during backoff, so the
+			// transient error is not a failure.
+			timer.Stop()
+			return nil
+		case <-timer.C():
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
+	"go.pact.im/x/clock/observeclock"
+)
+
+func newRestartTestClock() (*clock.Clock, *observeclock.Clock, *fakeclock.Clock) {
+	fakeClock := fakeclock.Go()
+	observeClock := observeclock.New(fakeClock)
+	return clock.NewClock(observeClock), observeClock, fakeClock
+}
+
+func noJitter(d time.Duration) time.Duration {
+	return d
+}
+
+func TestRestartBeforeReady(t *testing.T) {
+	oops := errors.New("oops")
+	c, observeClock, fakeClock := newRestartTestClock()
+
+	var runs, callbacks int
+	proc := Restart(RunnableFunc(func(ctx context.Context, callback Callback) error {
+		runs++
+		if runs < 3 {
+			return oops
+		}
+		return callback(ctx)
+	}), RestartPolicy{
+		Clock:      c,
+		MinBackoff: time.Second,
+		Jitter:     noJitter,
+	})
+
+	observe := observeClock.Observe()
+	done := make(chan error)
+	go func() {
+		done <- proc.Run(context.Background(), func(_ context.Context) error {
+			callbacks++
+			return nil
+		})
+	}()
+
+	for _, d := range []time.Duration{time.Second, 2 * time.Second} {
+		<-observe
+		observe = observeClock.Observe()
+		fakeClock.Add(d)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 3 {
+		t.Fatalf("expected 3 runs, got %d", runs)
+	}
+	if callbacks != 1 {
+		t.Fatalf("expected 1 callback invocation, got %d", callbacks)
+	}
+}
+
+func TestRestartAfterReady(t *testing.T) {
+	oops := errors.New("oops")
+	c, observeClock, fakeClock := newRestartTestClock()
+
+	fail := make(chan struct{})
+	restarted := make(chan struct{})
+
+	var runs, callbacks int
+	proc := Restart(RunnableFunc(func(ctx context.Context, callback Callback) error {
+		runs++
+		if runs > 1 {
+			close(restarted)
+			return callback(ctx)
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			select {
+			case <-ctx.Done():
+			case <-fail:
+				cancel()
+			}
+		}()
+		_ = callback(ctx)
+		return oops
+	}), RestartPolicy{
+		Clock:  c,
+		Jitter: noJitter,
+	})
+
+	observe := observeClock.Observe()
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- proc.Run(context.Background(), func(ctx context.Context) error {
+			callbacks++
+			close(fail)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-stop:
+			}
+			return nil
+		})
+	}()
+
+	<-observe
+	fakeClock.Add(defaultRestartMinBackoff)
+	<-restarted
+	close(stop)
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 2 {
+		t.Fatalf("expected 2 runs, got %d", runs)
+	}
+	if callbacks != 1 {
+		t.Fatalf("expected 1 callback invocation, got %d", callbacks)
+	}
+}
+
+func TestRestartPermanent(t *testing.T) {
+	oops := errors.New("oops")
+
+	var runs int
+	proc := Restart(RunnableFunc(func(_ context.Context, _ Callback) error {
+		runs++
+		return oops
+	}), RestartPolicy{
+		Permanent: func(err error) bool {
+			return errors.Is(err, oops)
+		},
+	})
+
+	err := proc.Run(context.Background(), func(_ context.Context) error {
+		t.Error("unexpected callback invocation")
+		return nil
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 1 {
+		t.Fatalf("expected 1 run, got %d", runs)
+	}
+}
+
+func TestRestartLimit(t *testing.T) {
+	oops := errors.New("oops")
+	c, observeClock, fakeClock := newRestartTestClock()
+
+	var runs int
+	proc := Restart(RunnableFunc(func(_ context.Context, _ Callback) error {
+		runs++
+		return oops
+	}), RestartPolicy{
+		Clock:       c,
+		MinBackoff:  time.Second,
+		MaxBackoff:  time.Second,
+		Jitter:      noJitter,
+		MaxRestarts: 2,
+	})
+
+	observe := observeClock.Observe()
+	done := make(chan error)
+	go func() {
+		done <- proc.Run(context.Background(), func(_ context.Context) error {
+			t.Error("unexpected callback invocation")
+			return nil
+		})
+	}()
+
+	for range 2 {
+		<-observe
+		observe = observeClock.Observe()
+		fakeClock.Add(time.Second)
+	}
+
+	err := <-done
+	if !errors.Is(err, ErrRestartLimit) || !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 3 {
+		t.Fatalf("expected 3 runs, got %d", runs)
+	}
+}
+
+func TestRestartBackoff(t *testing.T) {
+	p := RestartPolicy{
+		MinBackoff: time.Second,
+		MaxBackoff: 5 * time.Second,
+	}
+	p.setDefaults()
+
+	expected := []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		5 * time.Second,
+		5 * time.Second,
+	}
+	for n, d := range expected {
+		if got := p.backoff(uint(n)); got != d {
+			t.Fatalf("backoff(%d): expected %v, got %v", n, d, got)
+		}
+	}
+}
+
+func TestRestartStopDuringBackoff(t *testing.T) {
+	oops := errors.New("oops")
+	c, observeClock, _ := newRestartTestClock()
+
+	fail := make(chan struct{})
+	proc := Restart(RunnableFunc(func(ctx context.Context, callback Callback) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			select {
+			case <-ctx.Done():
+			case <-fail:
+				cancel()
+			}
+		}()
+		_ = callback(ctx)
+		return oops
+	}), RestartPolicy{
+		Clock:  c,
+		Jitter: noJitter,
+	})
+
+	observe := observeClock.Observe()
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- proc.Run(context.Background(), func(_ context.Context) error {
+			close(fail)
+			<-stop
+			return nil
+		})
+	}()
+
+	// Wait for the restart backoff timer and then request shutdown.
+	<-observe
+	close(stop)
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRestartStopDuringStartup(t *testing.T) {
+	oops := errors.New("oops")
+	c, observeClock, fakeClock := newRestartTestClock()
+
+	fail := make(chan struct{})
+	restarting := make(chan struct{})
+
+	var runs int
+	proc := Restart(RunnableFunc(func(ctx context.Context, callback Callback) error {
+		runs++
+		if runs > 1 {
+			// Never becomes ready, e.g. keeps reconnecting.
+			close(restarting)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			select {
+			case <-ctx.Done():
+			case <-fail:
+				cancel()
+			}
+		}()
+		_ = callback(ctx)
+		return oops
+	}), RestartPolicy{
+		Clock:  c,
+		Jitter: noJitter,
+	})
+
+	observe := observeClock.Observe()
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- proc.Run(context.Background(), func(_ context.Context) error {
+			close(fail)
+			<-stop
+			return nil
+		})
+	}()
+
+	<-observe
+	fakeClock.Add(defaultRestartMinBackoff)
+	<-restarting
+	close(stop)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the callback returned")
+	}
+}
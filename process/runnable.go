@@ -3,6 +3,8 @@ package process
 import (
 	"context"
 	"fmt"
+
+	"go.uber.org/zap"
 )
 
 // Callback is a type alias for the function passed to Runnable’s Run method.
@@ -58,21 +60,67 @@ func (*nopRunnable) Run(ctx context.Context, callback Callback) error {
 type namedRunnable struct {
 	proc Runnable
 	name string
+	log  *zap.Logger
+
+	readiness readiness
 }
 
 // Named returns a process that returns an error prefixed with name on failure.
-func Named(name string, p Runnable) Runnable {
+// If log is not nil, it is used to log process lifecycle events, i.e. startup,
+// readiness, shutdown and failure, annotated with the process name.
+//
+// The name is also used to identify the process in health reports and may be
+// retrieved using NameOf function.
+func Named(name string, p Runnable, log *zap.Logger) Runnable {
+	if log == nil {
+		log = zap.NewNop()
+	}
 	return &namedRunnable{
 		proc: p,
 		name: name,
+		log:  log.With(zap.String("process", name)),
 	}
 }
 
+// NameOf returns the name of the given process if it was created using Named
+// function.
+func NameOf(p Runnable) (string, bool) {
+	if p, ok := p.(*namedRunnable); ok {
+		return p.name, true
+	}
+	return "", false
+}
+
 // Run implements the Runnable interface.
 func (p *namedRunnable) Run(ctx context.Context, callback Callback) error {
-	err := p.proc.Run(ctx, callback)
+	p.log.Info("starting process")
+	err := p.proc.Run(ctx, p.readiness.wrap(func(ctx context.Context) error {
+		p.log.Info("process is ready")
+		err := callback(ctx)
+		p.log.Info("stopping process")
+		return err
+	}))
 	if err == nil {
+		p.log.Info("process stopped")
 		return nil
 	}
+	p.log.Error("process failed", zap.Error(err))
 	return fmt.Errorf("%s: %w", p.name, err)
 }
+
+// Healthy implements the HealthReporter interface.
+func (p *namedRunnable) Healthy(ctx context.Context) error {
+	err := p.readiness.err()
+	if err == nil {
+		err = healthOf(ctx, p.proc)
+	}
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", p.name, err)
+}
+
+// children implements the parentRunnable interface.
+func (p *namedRunnable) children() []Runnable {
+	return []Runnable{p.proc}
+}
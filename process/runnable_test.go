@@ -0,0 +1,51 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNamed(t *testing.T) {
+	oops := errors.New("oops")
+	core, logs := observer.New(zap.DebugLevel)
+
+	proc := Named("db", Nop(), zap.New(core))
+	if name, ok := NameOf(proc); !ok || name != "db" {
+		t.Fatalf("unexpected name: %q", name)
+	}
+
+	err := proc.Run(context.Background(), func(_ context.Context) error {
+		return oops
+	})
+	if !errors.Is(err, oops) || err.Error() != "db: oops" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var messages []string
+	for _, e := range logs.All() {
+		if e.ContextMap()["process"] != "db" {
+			t.Fatalf("unexpected log entry context: %v", e.ContextMap())
+		}
+		messages = append(messages, e.Message)
+	}
+	expected := []string{
+		"starting process",
+		"process is ready",
+		"stopping process",
+		"process failed",
+	}
+	if !slices.Equal(messages, expected) {
+		t.Fatalf("unexpected log messages: %q", messages)
+	}
+}
+
+func TestNameOf(t *testing.T) {
+	if _, ok := NameOf(Nop()); ok {
+		t.Fatal("unexpected name for unnamed process")
+	}
+}
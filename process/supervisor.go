@@ -0,0 +1,302 @@
+package process
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.pact.im/x/clock"
+)
+
+// SupervisorStrategy is a restart strategy for the Supervisor’s children.
+type SupervisorStrategy int
+
+const (
+	// OneForOne strategy restarts only the child that has failed.
+	OneForOne SupervisorStrategy = iota
+	// OneForAll strategy stops all children when any of them fails, and
+	// then restarts all children.
+	OneForAll
+)
+
+// ChildState represents the current state of the supervised child.
+type ChildState int
+
+const (
+	// ChildStopped is the initial state of the child. Child also enters
+	// this state after a graceful shutdown.
+	ChildStopped ChildState = iota
+	// ChildStarting is the state child enters when it is being started.
+	ChildStarting
+	// ChildRunning is the state child enters after a successful startup.
+	ChildRunning
+	// ChildRestarting is the state child enters when it is waiting to be
+	// restarted.
+	ChildRestarting
+	// ChildFailed is the state child enters when it terminates with an
+	// error.
+	ChildFailed
+)
+
+// String implements the fmt.Stringer interface.
+func (s ChildState) String() string {
+	switch s {
+	case ChildStopped:
+		return "stopped"
+	case ChildStarting:
+		return "starting"
+	case ChildRunning:
+		return "running"
+	case ChildRestarting:
+		return "restarting"
+	case ChildFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// Child is a named child process of the Supervisor.
+type Child struct {
+	// Name is the name of the child. It is used to annotate errors and
+	// logs. Defaults to the name of the Runnable if it was created using
+	// Named function.
+	Name string
+
+	// Runnable is the child process.
+	Runnable Runnable
+
+	// StopTimeout is the duration after graceful shutdown is requested
+	// that the child is given to terminate before its context is canceled.
+	// Zero value means no timeout.
+	StopTimeout time.Duration
+}
+
+// ChildStatus is a snapshot of the supervised child state.
+type ChildStatus struct {
+	// Name is the name of the child.
+	Name string
+	// State is the current state of the child.
+	State ChildState
+	// Restarts is the number of times the child has been restarted.
+	Restarts int
+	// Err is the last error returned by the child.
+	Err error
+}
+
+// SupervisorOptions is a set of options for supervisor constructor.
+type SupervisorOptions struct {
+	// Strategy is the restart strategy. Defaults to OneForOne.
+	Strategy SupervisorStrategy
+
+	// Restart is the restart policy for children. See RestartPolicy for
+	// default values.
+	Restart RestartPolicy
+
+	// Logger is the logger for child lifecycle events. Defaults to no-op
+	// logger.
+	Logger *zap.Logger
+}
+
+// setDefaults sets default values for unspecified options.
+func (o *SupervisorOptions) setDefaults() {
+	o.Restart.setDefaults()
+	if o.Logger == nil {
+		o.Logger = zap.NewNop()
+	}
+}
+
+// Supervisor is a Runnable that starts, stops, and restarts its named child
+// processes.
+//
+// Children are started in the order they are given and stopped in the reverse
+// order. A failed child is restarted according to the configured strategy and
+// restart policy. If a child fails permanently, all children are stopped and
+// Run returns the combined errors.
+type Supervisor struct {
+	proc       Runnable
+	supervised []*supervisedChild
+}
+
+// NewSupervisor returns a new Supervisor instance for the given children.
+func NewSupervisor(children []Child, o SupervisorOptions) *Supervisor {
+	o.setDefaults()
+
+	supervised := make([]*supervisedChild, len(children))
+	procs := make([]Runnable, len(children))
+	for i, c := range children {
+		name, named := NameOf(c.Runnable)
+		if named && c.Name == "" {
+			c.Name = name
+		}
+		proc := c.Runnable
+		if !named || name != c.Name {
+			proc = Named(c.Name, proc, nil)
+		}
+		sc := &supervisedChild{
+			proc:        proc,
+			clock:       o.Restart.Clock,
+			log:         o.Logger.With(zap.String("child", c.Name)),
+			stopTimeout: c.StopTimeout,
+			status: ChildStatus{
+				Name: c.Name,
+			},
+		}
+		supervised[i] = sc
+
+		var r Runnable = sc
+		if o.Strategy == OneForOne {
+			r = &restartRunnable{
+				proc:      r,
+				policy:    o.Restart,
+				onRestart: sc.restarting,
+			}
+		}
+		procs[i] = r
+	}
+
+	proc := Sequential(procs...)
+	if o.Strategy == OneForAll {
+		log := o.Logger
+		proc = &restartRunnable{
+			proc:   proc,
+			policy: o.Restart,
+			onRestart: func(err error, d time.Duration) {
+				log.Warn("restarting all children",
+					zap.Error(err),
+					zap.Duration("delay", d),
+				)
+				for _, sc := range supervised {
+					sc.restarting(nil, d)
+				}
+			},
+		}
+	}
+
+	return &Supervisor{
+		proc:       proc,
+		supervised: supervised,
+	}
+}
+
+// Run implements the Runnable interface. It must not be called concurrently.
+func (s *Supervisor) Run(ctx context.Context, callback Callback) error {
+	return s.proc.Run(ctx, callback)
+}
+
+// Healthy implements the HealthReporter interface.
+func (s *Supervisor) Healthy(ctx context.Context) error {
+	return healthOf(ctx, s.proc)
+}
+
+// children implements the parentRunnable interface.
+func (s *Supervisor) children() []Runnable {
+	return []Runnable{s.proc}
+}
+
+// Snapshot returns the current status of children in the start order.
+func (s *Supervisor) Snapshot() []ChildStatus {
+	xs := make([]ChildStatus, len(s.supervised))
+	for i, c := range s.supervised {
+		xs[i] = c.snapshot()
+	}
+	return xs
+}
+
+// supervisedChild is a Runnable that tracks the state of the child process.
+type supervisedChild struct {
+	proc        Runnable
+	clock       *clock.Clock
+	log         *zap.Logger
+	stopTimeout time.Duration
+
+	mu     sync.Mutex
+	status ChildStatus
+}
+
+// Run implements the Runnable interface.
+func (c *supervisedChild) Run(ctx context.Context, callback Callback) error {
+	// Context is canceled to force shutdown on stop timeout.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c.setState(ChildStarting, nil)
+	c.log.Info("starting child")
+
+	var event clock.Event
+	err := c.proc.Run(ctx, func(ctx context.Context) error {
+		c.setState(ChildRunning, nil)
+		c.log.Info("child is running")
+
+		err := callback(ctx)
+
+		c.log.Info("stopping child")
+		if c.stopTimeout > 0 {
+			event = c.clock.Schedule(c.stopTimeout, func(_ time.Time) {
+				c.log.Warn("child stop timeout exceeded, forcing shutdown",
+					zap.Duration("timeout", c.stopTimeout),
+				)
+				cancel()
+			})
+		}
+		return err
+	})
+	if event != nil {
+		event.Stop()
+	}
+
+	if err != nil {
+		c.setState(ChildFailed, err)
+		c.log.Error("child failed", zap.Error(err))
+		return err
+	}
+	c.setState(ChildStopped, nil)
+	c.log.Info("child stopped")
+	return nil
+}
+
+// Healthy implements the HealthReporter interface.
+func (c *supervisedChild) Healthy(ctx context.Context) error {
+	if c.snapshot().State != ChildRunning {
+		return ErrNotReady
+	}
+	return healthOf(ctx, c.proc)
+}
+
+// children implements the parentRunnable interface.
+func (c *supervisedChild) children() []Runnable {
+	return []Runnable{c.proc}
+}
+
+// restarting updates the child state when it is scheduled for restart.
+func (c *supervisedChild) restarting(err error, d time.Duration) {
+	c.mu.Lock()
+	c.status.State = ChildRestarting
+	c.status.Restarts++
+	c.mu.Unlock()
+
+	if err != nil {
+		c.log.Warn("restarting child",
+			zap.Error(err),
+			zap.Duration("delay", d),
+		)
+	}
+}
+
+// setState sets the child state and the last error if err is not nil.
+func (c *supervisedChild) setState(state ChildState, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status.State = state
+	if err != nil {
+		c.status.Err = err
+	}
+}
+
+// snapshot returns the current child status.
+func (c *supervisedChild) snapshot() ChildStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
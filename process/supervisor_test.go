@@ -0,0 +1,255 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// supervisorTestChild is a child process for Supervisor tests that fails
+// after startup when requested.
+type supervisorTestChild struct {
+	name   string
+	events chan<- string
+
+	mu   sync.Mutex
+	runs int
+	fail chan struct{}
+}
+
+func newSupervisorTestChild(name string, events chan<- string) *supervisorTestChild {
+	return &supervisorTestChild{
+		name:   name,
+		events: events,
+		fail:   make(chan struct{}),
+	}
+}
+
+func (c *supervisorTestChild) Run(ctx context.Context, callback Callback) error {
+	c.mu.Lock()
+	c.runs++
+	fail := c.fail
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	failed := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-fail:
+			close(failed)
+			cancel()
+		}
+	}()
+
+	c.events <- "start " + c.name
+	err := callback(ctx)
+	c.events <- "stop " + c.name
+
+	select {
+	case <-failed:
+		return errors.New("oops")
+	default:
+	}
+	return err
+}
+
+func (c *supervisorTestChild) Fail() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(c.fail)
+	c.fail = make(chan struct{})
+}
+
+func (c *supervisorTestChild) Runs() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.runs
+}
+
+func expectEvents(t *testing.T, events <-chan string, expected ...string) {
+	t.Helper()
+	for _, e := range expected {
+		if got := <-events; got != e {
+			t.Fatalf("expected %q event, got %q", e, got)
+		}
+	}
+}
+
+func expectEventsUnordered(t *testing.T, events <-chan string, expected ...string) {
+	t.Helper()
+	got := make([]string, len(expected))
+	for i := range got {
+		got[i] = <-events
+	}
+	slices.Sort(got)
+	slices.Sort(expected)
+	if !slices.Equal(got, expected) {
+		t.Fatalf("expected %q events, got %q", expected, got)
+	}
+}
+
+func testSupervisor(t *testing.T, strategy SupervisorStrategy) {
+	c, observeClock, fakeClock := newRestartTestClock()
+	core, logs := observer.New(zap.DebugLevel)
+
+	events := make(chan string, 16)
+	a := newSupervisorTestChild("a", events)
+	b := newSupervisorTestChild("b", events)
+
+	s := NewSupervisor([]Child{
+		{Name: "a", Runnable: a},
+		{Name: "b", Runnable: b},
+	}, SupervisorOptions{
+		Strategy: strategy,
+		Restart: RestartPolicy{
+			Clock:  c,
+			Jitter: noJitter,
+		},
+		Logger: zap.New(core),
+	})
+
+	ready := make(chan struct{})
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- s.Run(context.Background(), func(_ context.Context) error {
+			close(ready)
+			<-stop
+			return nil
+		})
+	}()
+
+	expectEvents(t, events, "start a", "start b")
+	<-ready
+
+	observe := observeClock.Observe()
+	a.Fail()
+	switch strategy {
+	case OneForOne:
+		expectEvents(t, events, "stop a")
+	case OneForAll:
+		expectEventsUnordered(t, events, "stop a", "stop b")
+	}
+	<-observe
+
+	snapshot := s.Snapshot()
+	if snapshot[0].State != ChildRestarting || snapshot[0].Restarts != 1 {
+		t.Fatalf("unexpected snapshot: %+v", snapshot)
+	}
+
+	fakeClock.Add(defaultRestartMinBackoff)
+	switch strategy {
+	case OneForOne:
+		expectEvents(t, events, "start a")
+	case OneForAll:
+		expectEvents(t, events, "start a", "start b")
+	}
+
+	// Wait for the restarted children to report readiness, otherwise they
+	// are shut down forcibly in no particular order.
+	for s.Healthy(context.Background()) != nil {
+		time.Sleep(time.Millisecond)
+	}
+
+	close(stop)
+	expectEvents(t, events, "stop b", "stop a")
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedRuns := map[SupervisorStrategy]int{
+		OneForOne: 1,
+		OneForAll: 2,
+	}
+	if a.Runs() != 2 || b.Runs() != expectedRuns[strategy] {
+		t.Fatalf("unexpected runs: a=%d b=%d", a.Runs(), b.Runs())
+	}
+
+	snapshot = s.Snapshot()
+	for _, st := range snapshot {
+		if st.State != ChildStopped {
+			t.Fatalf("unexpected snapshot: %+v", snapshot)
+		}
+	}
+	if snapshot[0].Err == nil || snapshot[1].Err != nil {
+		t.Fatalf("unexpected snapshot errors: %+v", snapshot)
+	}
+
+	failed := logs.FilterMessage("child failed").All()
+	if len(failed) != 1 || failed[0].ContextMap()["child"] != "a" {
+		t.Fatalf("unexpected logs: %+v", failed)
+	}
+}
+
+func TestSupervisorOneForOne(t *testing.T) {
+	testSupervisor(t, OneForOne)
+}
+
+func TestSupervisorOneForAll(t *testing.T) {
+	testSupervisor(t, OneForAll)
+}
+
+func TestSupervisorStopTimeout(t *testing.T) {
+	c, observeClock, fakeClock := newRestartTestClock()
+
+	s := NewSupervisor([]Child{{
+		Name: "stuck",
+		Runnable: Leaf(func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}, func(ctx context.Context) error {
+			// Ignore graceful shutdown request.
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		StopTimeout: time.Second,
+	}}, SupervisorOptions{
+		Restart: RestartPolicy{
+			Clock: c,
+		},
+	})
+
+	observe := observeClock.Observe()
+	done := make(chan error)
+	go func() {
+		done <- s.Run(context.Background(), func(_ context.Context) error {
+			return nil
+		})
+	}()
+
+	<-observe
+	fakeClock.Add(time.Second)
+
+	err := <-done
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestChildStateString(t *testing.T) {
+	states := []ChildState{
+		ChildStopped,
+		ChildStarting,
+		ChildRunning,
+		ChildRestarting,
+		ChildFailed,
+	}
+	var names []string
+	for _, s := range states {
+		names = append(names, s.String())
+	}
+	expected := []string{"stopped", "starting", "running", "restarting", "failed"}
+	if !slices.Equal(names, expected) {
+		t.Fatalf("unexpected names: %q", names)
+	}
+}
@@ -3,6 +3,9 @@ package process
 import (
 	"context"
 	"sync"
+	"time"
+
+	"go.uber.org/multierr"
 )
 
 // Leaf converts a “leaf” function to a runnable process function that accepts
@@ -104,20 +107,60 @@ func (r *leafRunnable) Run(ctx context.Context, callback Callback) error {
 	return runError
 }
 
+// defaultStartStopTimeout is the default timeout for the stop function of
+// StartStop.
+const defaultStartStopTimeout = 30 * time.Second
+
+// StartStopOptions is a set of options for StartStopWithOptions function.
+type StartStopOptions struct {
+	// StopTimeout is the timeout for the stop function. Defaults to 30
+	// seconds.
+	StopTimeout time.Duration
+}
+
+// setDefaults sets default values for unspecified options.
+func (o *StartStopOptions) setDefaults() {
+	if o.StopTimeout <= 0 {
+		o.StopTimeout = defaultStartStopTimeout
+	}
+}
+
 // StartStop returns a Runnable instance for the pair of start/stop functions.
 // The stop function should perform a graceful shutdown until a context expires,
 // then proceed with a forced shutdown.
 //
-// The resulting Runnable returns either start error or the first non-nil error
-// from callback and stop functions. If both callback and stop return a non-nil
-// error, the latter is ignored.
+// The resulting Runnable calls start, invokes callback once start succeeds and
+// calls stop exactly once after the callback returns. If start fails, neither
+// callback nor stop are called.
+//
+// The context passed to stop expires after the stop timeout, or earlier if the
+// parent context is canceled to force shutdown. See StartStopWithOptions for
+// configuring the timeout.
+//
+// It returns either start error or the combined errors from callback and stop
+// functions, in that order.
+//
+// For a simple function that runs until the context is canceled, use Leaf with
+// nil gracefulStop function.
 func StartStop(startInBackground, gracefulStop func(ctx context.Context) error) Runnable {
-	return &startStopRunnable{startInBackground, gracefulStop}
+	return StartStopWithOptions(startInBackground, gracefulStop, StartStopOptions{})
+}
+
+// StartStopWithOptions returns a Runnable instance for the pair of start/stop
+// functions using the given options. See StartStop for more details.
+func StartStopWithOptions(startInBackground, gracefulStop func(ctx context.Context) error, o StartStopOptions) Runnable {
+	o.setDefaults()
+	return &startStopRunnable{
+		startInBackground: startInBackground,
+		gracefulStop:      gracefulStop,
+		stopTimeout:       o.StopTimeout,
+	}
 }
 
 type startStopRunnable struct {
 	startInBackground func(ctx context.Context) error
 	gracefulStop      func(ctx context.Context) error
+	stopTimeout       time.Duration
 }
 
 func (r *startStopRunnable) Run(ctx context.Context, callback Callback) error {
@@ -125,8 +168,10 @@ func (r *startStopRunnable) Run(ctx context.Context, callback Callback) error {
 		return err
 	}
 	callbackError := callback(ctx)
-	if err := r.gracefulStop(ctx); err != nil && callbackError == nil {
-		return err
-	}
-	return callbackError
+
+	stopCtx, cancel := context.WithTimeout(ctx, r.stopTimeout)
+	defer cancel()
+	stopError := r.gracefulStop(stopCtx)
+
+	return multierr.Append(callbackError, stopError)
 }
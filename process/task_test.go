@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
+
+	"go.uber.org/multierr"
 )
 
 func TestLeafCallbackReturns(t *testing.T) {
@@ -116,18 +119,65 @@ func TestStartStopErrorOnCallback(t *testing.T) {
 
 func TestStartStopErrorOnCallbackAndStop(t *testing.T) {
 	oops := errors.New("oops")
+	stop := errors.New("stop")
 	proc := StartStop(
 		func(_ context.Context) error { return nil },
-		func(_ context.Context) error { return errors.New("ignored") },
+		func(_ context.Context) error { return stop },
 	)
 	err := proc.Run(context.Background(), func(_ context.Context) error {
 		return oops
 	})
+	if !errors.Is(err, oops) || !errors.Is(err, stop) {
+		t.FailNow()
+	}
+	if errs := multierr.Errors(err); len(errs) != 2 || errs[0] != oops || errs[1] != stop {
+		t.FailNow()
+	}
+}
+
+func TestStartStopErrorOnStartSkipsCallback(t *testing.T) {
+	oops := errors.New("oops")
+	proc := StartStop(
+		func(_ context.Context) error { return oops },
+		func(_ context.Context) error { panic("unreachable") },
+	)
+	err := proc.Run(context.Background(), func(_ context.Context) error {
+		panic("unreachable")
+	})
 	if !errors.Is(err, oops) {
 		t.FailNow()
 	}
 }
 
+func TestStartStopCalledOnce(t *testing.T) {
+	var starts, stops int
+	proc := StartStop(
+		func(_ context.Context) error {
+			starts++
+			return nil
+		},
+		func(_ context.Context) error {
+			stops++
+			return nil
+		},
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	err := proc.Run(ctx, func(ctx context.Context) error {
+		if stops != 0 {
+			t.Error("stop called before callback returned")
+		}
+		cancel()
+		<-ctx.Done()
+		return nil
+	})
+	if err != nil {
+		t.FailNow()
+	}
+	if starts != 1 || stops != 1 {
+		t.Fatalf("expected start and stop to be called once, got %d and %d", starts, stops)
+	}
+}
+
 func TestStartStopErrorOnStop(t *testing.T) {
 	oops := errors.New("oops")
 	proc := StartStop(
@@ -141,3 +191,56 @@ func TestStartStopErrorOnStop(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestStartStopStopTimeout(t *testing.T) {
+	const timeout = time.Minute
+
+	var stopErr error
+	var deadline time.Time
+	proc := StartStopWithOptions(
+		func(_ context.Context) error { return nil },
+		func(ctx context.Context) error {
+			stopErr = ctx.Err()
+			deadline, _ = ctx.Deadline()
+			return nil
+		},
+		StartStopOptions{StopTimeout: timeout},
+	)
+
+	err := proc.Run(context.Background(), func(_ context.Context) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stopErr != nil {
+		t.Fatalf("expected live stop context, got %v", stopErr)
+	}
+	if deadline.IsZero() || deadline.After(time.Now().Add(timeout)) {
+		t.Fatalf("expected stop context deadline within %v, got %v", timeout, deadline)
+	}
+}
+
+func TestStartStopParentCanceled(t *testing.T) {
+	var stopErr error
+	proc := StartStop(
+		func(_ context.Context) error { return nil },
+		func(ctx context.Context) error {
+			stopErr = ctx.Err()
+			return nil
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := proc.Run(ctx, func(ctx context.Context) error {
+		cancel()
+		<-ctx.Done()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(stopErr, context.Canceled) {
+		t.Fatalf("expected canceled stop context, got %v", stopErr)
+	}
+}
@@ -0,0 +1,197 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"go.pact.im/x/clock"
+)
+
+var (
+	// ErrStartTimeout is an error that is returned if the process does not
+	// call the callback within the start timeout.
+	ErrStartTimeout = errors.New("process: start timeout exceeded")
+
+	// ErrStopTimeout is an error that is returned if the process does not
+	// terminate within the stop timeout.
+	ErrStopTimeout = errors.New("process: stop timeout exceeded")
+)
+
+const (
+	startTimeoutPending int32 = iota
+	startTimeoutStarted
+	startTimeoutExpired
+)
+
+// TimeoutOptions is a set of options for WithStartTimeoutOptions and
+// WithStopTimeoutOptions functions.
+type TimeoutOptions struct {
+	// Clock is the clock used for timeouts. Defaults to system clock.
+	Clock *clock.Clock
+}
+
+// setDefaults sets default values for unspecified options.
+func (o *TimeoutOptions) setDefaults() {
+	if o.Clock == nil {
+		o.Clock = clock.System()
+	}
+}
+
+// WithStartTimeout returns a Runnable instance that cancels the context of the
+// given process and returns ErrStartTimeout error if the callback has not been
+// invoked within duration d. If the process is named (possibly under other
+// wrappers, e.g. CatchPanics), the error is prefixed with its name.
+func WithStartTimeout(r Runnable, d time.Duration) Runnable {
+	return WithStartTimeoutOptions(r, d, TimeoutOptions{})
+}
+
+// WithStartTimeoutOptions is like WithStartTimeout but accepts additional
+// options.
+func WithStartTimeoutOptions(r Runnable, d time.Duration, o TimeoutOptions) Runnable {
+	o.setDefaults()
+	return &startTimeoutRunnable{
+		proc:    r,
+		clock:   o.Clock,
+		timeout: d,
+	}
+}
+
+// startTimeoutRunnable is a Runnable that fails if the underlying process does
+// not report readiness within the given timeout.
+type startTimeoutRunnable struct {
+	proc    Runnable
+	clock   *clock.Clock
+	timeout time.Duration
+}
+
+// Run implements the Runnable interface.
+func (r *startTimeoutRunnable) Run(ctx context.Context, callback Callback) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var state atomic.Int32
+	event := r.clock.Schedule(r.timeout, func(_ time.Time) {
+		if state.CompareAndSwap(startTimeoutPending, startTimeoutExpired) {
+			cancel()
+		}
+	})
+	defer event.Stop()
+
+	err := r.proc.Run(ctx, func(ctx context.Context) error {
+		if !state.CompareAndSwap(startTimeoutPending, startTimeoutStarted) {
+			return ctx.Err()
+		}
+		event.Stop()
+		return callback(ctx)
+	})
+	if state.Load() == startTimeoutExpired {
+		return namedError(r.proc, ErrStartTimeout)
+	}
+	return err
+}
+
+// Healthy implements the HealthReporter interface.
+func (r *startTimeoutRunnable) Healthy(ctx context.Context) error {
+	return healthOf(ctx, r.proc)
+}
+
+// children implements the parentRunnable interface.
+func (r *startTimeoutRunnable) children() []Runnable {
+	return []Runnable{r.proc}
+}
+
+// WithStopTimeout returns a Runnable instance that returns ErrStopTimeout error
+// if the given process does not terminate within duration d after either the
+// callback returns or the context expires. If the process is named (possibly
+// under other wrappers), the error is prefixed with its name.
+//
+// Note that the process is run in a separate goroutine that keeps running in
+// background after the timeout. Such goroutines are leaked and should be
+// detected in tests, e.g. using go.uber.org/goleak package.
+func WithStopTimeout(r Runnable, d time.Duration) Runnable {
+	return WithStopTimeoutOptions(r, d, TimeoutOptions{})
+}
+
+// WithStopTimeoutOptions is like WithStopTimeout but accepts additional
+// options.
+func WithStopTimeoutOptions(r Runnable, d time.Duration, o TimeoutOptions) Runnable {
+	o.setDefaults()
+	return &stopTimeoutRunnable{
+		proc:    r,
+		clock:   o.Clock,
+		timeout: d,
+	}
+}
+
+// stopTimeoutRunnable is a Runnable that stops waiting for the underlying
+// process if it does not terminate within the given timeout after shutdown.
+type stopTimeoutRunnable struct {
+	proc    Runnable
+	clock   *clock.Clock
+	timeout time.Duration
+}
+
+// Run implements the Runnable interface.
+func (r *stopTimeoutRunnable) Run(ctx context.Context, callback Callback) error {
+	var once sync.Once
+	stopping := make(chan struct{})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- r.proc.Run(ctx, func(ctx context.Context) error {
+			defer once.Do(func() { close(stopping) })
+			return callback(ctx)
+		})
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-stopping:
+	case <-ctx.Done():
+	}
+
+	timer := r.clock.Timer(r.timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C():
+		return namedError(r.proc, ErrStopTimeout)
+	}
+}
+
+// Healthy implements the HealthReporter interface.
+func (r *stopTimeoutRunnable) Healthy(ctx context.Context) error {
+	return healthOf(ctx, r.proc)
+}
+
+// children implements the parentRunnable interface.
+func (r *stopTimeoutRunnable) children() []Runnable {
+	return []Runnable{r.proc}
+}
+
+// namedError prefixes err with the name of the given process if it is named.
+// It looks through single-process wrappers to find the name.
+func namedError(r Runnable, err error) error {
+	for {
+		if p, ok := r.(*namedRunnable); ok {
+			return fmt.Errorf("%s: %w", p.name, err)
+		}
+		p, ok := r.(parentRunnable)
+		if !ok {
+			return err
+		}
+		children := p.children()
+		if len(children) != 1 {
+			return err
+		}
+		r = children[0]
+	}
+}
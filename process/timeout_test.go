@@ -0,0 +1,126 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStartTimeout(t *testing.T) {
+	c, observeClock, fakeClock := newRestartTestClock()
+
+	proc := WithStartTimeoutOptions(Named("stuck", RunnableFunc(func(ctx context.Context, _ Callback) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), nil), time.Second, TimeoutOptions{Clock: c})
+
+	observe := observeClock.Observe()
+	done := make(chan error)
+	go func() {
+		done <- proc.Run(context.Background(), func(_ context.Context) error {
+			t.Error("unexpected callback invocation")
+			return nil
+		})
+	}()
+
+	<-observe
+	fakeClock.Add(time.Second)
+
+	err := <-done
+	if !errors.Is(err, ErrStartTimeout) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err.Error() != "stuck: "+ErrStartTimeout.Error() {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
+
+func TestStartTimeoutNotExceeded(t *testing.T) {
+	c, _, fakeClock := newRestartTestClock()
+
+	oops := errors.New("oops")
+	proc := WithStartTimeoutOptions(Nop(), time.Second, TimeoutOptions{Clock: c})
+	err := proc.Run(context.Background(), func(ctx context.Context) error {
+		fakeClock.Add(time.Second)
+		if ctx.Err() != nil {
+			t.Error("unexpected context cancellation")
+		}
+		return oops
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStopTimeout(t *testing.T) {
+	c, observeClock, fakeClock := newRestartTestClock()
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	proc := WithStopTimeoutOptions(Named("stuck", RunnableFunc(func(ctx context.Context, callback Callback) error {
+		err := callback(ctx)
+		<-release
+		return err
+	}), nil), time.Second, TimeoutOptions{Clock: c})
+
+	observe := observeClock.Observe()
+	done := make(chan error)
+	go func() {
+		done <- proc.Run(context.Background(), func(_ context.Context) error {
+			return nil
+		})
+	}()
+
+	<-observe
+	fakeClock.Add(time.Second)
+
+	err := <-done
+	if !errors.Is(err, ErrStopTimeout) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err.Error() != "stuck: "+ErrStopTimeout.Error() {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
+
+func TestStopTimeoutNotExceeded(t *testing.T) {
+	oops := errors.New("oops")
+	proc := WithStopTimeout(Nop(), time.Second)
+	err := proc.Run(context.Background(), func(_ context.Context) error {
+		return oops
+	})
+	if !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStopTimeoutWrappedName(t *testing.T) {
+	c, observeClock, fakeClock := newRestartTestClock()
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	proc := WithStopTimeoutOptions(CatchPanics(Named("stuck", RunnableFunc(func(ctx context.Context, callback Callback) error {
+		err := callback(ctx)
+		<-release
+		return err
+	}), nil)), time.Second, TimeoutOptions{Clock: c})
+
+	observe := observeClock.Observe()
+	done := make(chan error)
+	go func() {
+		done <- proc.Run(context.Background(), func(_ context.Context) error {
+			return nil
+		})
+	}()
+
+	<-observe
+	fakeClock.Add(time.Second)
+
+	err := <-done
+	if err.Error() != "stuck: "+ErrStopTimeout.Error() {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package zaplog
+
+import (
+	"io"
+	"os"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/term"
+)
+
+// terminalTimeLayout is the time layout used for terminal output.
+const terminalTimeLayout = "15:04:05.000"
+
+// ANSI escape sequences for level colors.
+const (
+	colorReset   = "\x1b[0m"
+	colorRed     = "\x1b[31m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+)
+
+// isTerminal reports whether w is a terminal. It returns false for writers
+// other than *os.File.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || f == nil {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// useColor reports whether colored output should be used for w. If color is
+// nil, it detects whether w is a terminal that supports colors.
+func useColor(w io.Writer, color *bool) bool {
+	if color != nil {
+		return *color
+	}
+	if !isTerminal(w) {
+		return false
+	}
+	// isTerminal guarantees that w is *os.File.
+	return enableVirtualTerminal(w.(*os.File))
+}
+
+// shortLevel returns a short tag for the level.
+func shortLevel(l zapcore.Level) string {
+	switch l {
+	case zapcore.DebugLevel:
+		return "DBG"
+	case zapcore.InfoLevel:
+		return "INF"
+	case zapcore.WarnLevel:
+		return "WRN"
+	case zapcore.ErrorLevel:
+		return "ERR"
+	case zapcore.DPanicLevel:
+		return "DPN"
+	case zapcore.PanicLevel:
+		return "PNC"
+	case zapcore.FatalLevel:
+		return "FTL"
+	}
+	return l.CapitalString()
+}
+
+// levelColor returns ANSI color escape sequence for the level.
+func levelColor(l zapcore.Level) string {
+	switch {
+	case l <= zapcore.DebugLevel:
+		return colorMagenta
+	case l == zapcore.InfoLevel:
+		return colorBlue
+	case l == zapcore.WarnLevel:
+		return colorYellow
+	}
+	return colorRed
+}
+
+// shortLevelEncoder is a zapcore.LevelEncoder that encodes levels as short
+// tags.
+func shortLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(shortLevel(l))
+}
+
+// shortColorLevelEncoder is a zapcore.LevelEncoder that encodes levels as
+// short colored tags.
+func shortColorLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(levelColor(l) + shortLevel(l) + colorReset)
+}
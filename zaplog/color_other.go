@@ -0,0 +1,11 @@
+//go:build !windows
+
+package zaplog
+
+import (
+	"os"
+)
+
+// enableVirtualTerminal is a no-op on non-Windows platforms since terminals
+// support ANSI escape sequences.
+func enableVirtualTerminal(*os.File) bool { return true }
@@ -0,0 +1,90 @@
+package zaplog_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"go.pact.im/x/zaplog"
+)
+
+func TestAutoEncodingNotTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	logAll(zaplog.New(&buf,
+		zaplog.WithEncoding(zaplog.EncodingAuto),
+		zaplog.WithLevel(zap.ErrorLevel),
+		zaplog.WithCaller(false),
+	))
+
+	const want = `{"level":"error","time":"2022-02-24T04:00:00.000Z","logger":"test","msg":"error","n":42}
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAutoEncodingFile(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	done := make(chan []byte)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- b
+	}()
+
+	logAll(zaplog.New(w,
+		zaplog.WithEncoding(zaplog.EncodingAuto),
+		zaplog.WithLevel(zap.ErrorLevel),
+		zaplog.WithCaller(false),
+	))
+	_ = w.Close()
+
+	const want = `{"level":"error","time":"2022-02-24T04:00:00.000Z","logger":"test","msg":"error","n":42}
+`
+	if got := string(<-done); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Must not panic on nil file.
+	_ = zaplog.New((*os.File)(nil), zaplog.WithEncoding(zaplog.EncodingAuto))
+}
+
+func TestConsoleColor(t *testing.T) {
+	var buf bytes.Buffer
+	logAll(zaplog.New(&buf,
+		zaplog.WithColor(true),
+		zaplog.WithLevel(zap.ErrorLevel),
+		zaplog.WithCaller(false),
+	))
+
+	const want = "2022-02-24T04:00:00.000Z\t\x1b[31merror\x1b[0m\ttest\terror\t{\"n\": 42}\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestFromEnvNoColor(t *testing.T) {
+	t.Setenv("LOG_COLOR", "true")
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("LOG_CALLER", "0")
+
+	var buf bytes.Buffer
+	log, err := zaplog.FromEnv(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logAll(log)
+
+	const want = "2022-02-24T04:00:00.000Z\terror\ttest\terror\t{\"n\": 42}\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output:\n%q\nwant:\n%q", got, want)
+	}
+}
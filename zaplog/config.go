@@ -0,0 +1,252 @@
+package zaplog
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Encoding is the name of the log entry encoding.
+type Encoding string
+
+const (
+	// EncodingConsole is a human-readable encoding with structured context
+	// appended as JSON.
+	EncodingConsole Encoding = "console"
+	// EncodingJSON is a machine-readable encoding with one JSON object per
+	// log entry.
+	EncodingJSON Encoding = "json"
+	// EncodingAuto selects a human-readable console encoding with short
+	// level tags and timestamps if the writer is a terminal, and JSON
+	// encoding otherwise.
+	EncodingAuto Encoding = "auto"
+)
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. It returns
+// an error if the text is not a known encoding name.
+func (e *Encoding) UnmarshalText(text []byte) error {
+	v, err := parseEncoding(string(text))
+	if err != nil {
+		return fmt.Errorf("zaplog: %w", err)
+	}
+	*e = v
+	return nil
+}
+
+// parseEncoding parses the encoding name.
+func parseEncoding(s string) (Encoding, error) {
+	switch v := Encoding(s); v {
+	case EncodingConsole, EncodingJSON, EncodingAuto:
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown encoding %q (accepted values: %s, %s, %s)",
+		s, EncodingConsole, EncodingJSON, EncodingAuto,
+	)
+}
+
+// parseLevel parses the logging level name.
+func parseLevel(s string) (zapcore.Level, error) {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(s)); err != nil {
+		var names []string
+		for l := zapcore.DebugLevel; l <= zapcore.FatalLevel; l++ {
+			names = append(names, l.String())
+		}
+		return lvl, fmt.Errorf("unknown level %q (accepted values: %s)",
+			s, strings.Join(names, ", "),
+		)
+	}
+	return lvl, nil
+}
+
+// Well-known time layout names that are accepted in addition to arbitrary
+// time.Time layouts.
+const (
+	TimeLayoutISO8601     = "iso8601"
+	TimeLayoutRFC3339     = "rfc3339"
+	TimeLayoutRFC3339Nano = "rfc3339nano"
+	TimeLayoutEpoch       = "epoch"
+	TimeLayoutEpochMillis = "epochmillis"
+	TimeLayoutEpochNanos  = "epochnanos"
+)
+
+// defaultConfig is the default configuration that we use if Config is nil.
+var defaultConfig = Config{
+	Encoding:   EncodingConsole,
+	TimeLayout: TimeLayoutISO8601,
+}
+
+// Config contains the options for the logger. It can be unmarshaled from JSON
+// or YAML. Zero values are replaced with defaults.
+type Config struct {
+	// Level is the minimum enabled logging level. Defaults to debug level.
+	Level *zapcore.Level `json:"level" yaml:"level"`
+	// Levels contains per-logger level overrides keyed by the logger name.
+	// See Levels type for details.
+	Levels map[string]zapcore.Level `json:"levels" yaml:"levels"`
+	// Encoding is the log entry encoding. Defaults to console encoding.
+	Encoding Encoding `json:"encoding" yaml:"encoding"`
+	// TimeLayout is the timestamp format. It is either one of TimeLayout*
+	// names or a layout string for time.Time.Format. Defaults to ISO8601.
+	TimeLayout string `json:"timeLayout" yaml:"timeLayout"`
+	// Color controls colored level output for console-based encodings. If
+	// nil, colors are used for auto encoding when the writer is a terminal
+	// that supports them, and are not used for console encoding.
+	Color *bool `json:"color" yaml:"color"`
+	// DisableCaller disables annotating log entries with the caller’s file
+	// name and line number.
+	DisableCaller bool `json:"disableCaller" yaml:"disableCaller"`
+	// Stacktrace is the level at and above which stack traces are captured.
+	// Defaults to no stack traces.
+	Stacktrace *zapcore.Level `json:"stacktrace" yaml:"stacktrace"`
+
+	// sinks is a list of additional log destinations.
+	sinks []sinkConfig
+}
+
+// setDefaults sets default values for zero fields.
+func (c *Config) setDefaults() {
+	if c.Encoding == "" {
+		c.Encoding = defaultConfig.Encoding
+	}
+	if c.TimeLayout == "" {
+		c.TimeLayout = defaultConfig.TimeLayout
+	}
+}
+
+// level returns the minimum enabled logging level.
+func (c *Config) level() zapcore.Level {
+	if c.Level == nil {
+		return zapcore.DebugLevel
+	}
+	return *c.Level
+}
+
+// core returns the zapcore.Core that writes to w and filters entries using the
+// given levels.
+func (c *Config) core(w io.Writer, levels *Levels) zapcore.Core {
+	return &levelCore{
+		Core: zapcore.NewCore(
+			c.encoder(w),
+			zapcore.AddSync(w),
+			allLevels,
+		),
+		levels: levels,
+	}
+}
+
+// encoder returns the zapcore.Encoder for the configuration and writer w.
+func (c *Config) encoder(w io.Writer) zapcore.Encoder {
+	ec := zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		LevelKey:       "level",
+		TimeKey:        "time",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     timeEncoder(c.TimeLayout),
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.FullCallerEncoder,
+	}
+	if c.Stacktrace != nil {
+		ec.StacktraceKey = "stacktrace"
+	}
+	switch c.Encoding {
+	case EncodingJSON:
+		return zapcore.NewJSONEncoder(ec)
+	case EncodingAuto:
+		if !isTerminal(w) {
+			return zapcore.NewJSONEncoder(ec)
+		}
+		ec.EncodeTime = zapcore.TimeEncoderOfLayout(terminalTimeLayout)
+		ec.EncodeCaller = zapcore.ShortCallerEncoder
+		ec.EncodeLevel = shortLevelEncoder
+		if useColor(w, c.Color) {
+			ec.EncodeLevel = shortColorLevelEncoder
+		}
+	default:
+		if c.Color != nil && *c.Color {
+			ec.EncodeLevel = zapcore.LowercaseColorLevelEncoder
+		}
+	}
+	return zapcore.NewConsoleEncoder(ec)
+}
+
+// timeEncoder returns the zapcore.TimeEncoder for the given layout.
+func timeEncoder(layout string) zapcore.TimeEncoder {
+	switch strings.ToLower(layout) {
+	case TimeLayoutISO8601:
+		return zapcore.ISO8601TimeEncoder
+	case TimeLayoutRFC3339:
+		return zapcore.RFC3339TimeEncoder
+	case TimeLayoutRFC3339Nano:
+		return zapcore.RFC3339NanoTimeEncoder
+	case TimeLayoutEpoch:
+		return zapcore.EpochTimeEncoder
+	case TimeLayoutEpochMillis:
+		return zapcore.EpochMillisTimeEncoder
+	case TimeLayoutEpochNanos:
+		return zapcore.EpochNanosTimeEncoder
+	}
+	return zapcore.TimeEncoderOfLayout(layout)
+}
+
+// Option modifies the given configuration for the logger.
+type Option func(*Config)
+
+// WithLevel sets the Level configuration option.
+func WithLevel(level zapcore.Level) Option {
+	return func(c *Config) {
+		c.Level = &level
+	}
+}
+
+// WithNamedLevel adds an entry to the Levels configuration option.
+func WithNamedLevel(name string, level zapcore.Level) Option {
+	return func(c *Config) {
+		c.Levels = maps.Clone(c.Levels)
+		if c.Levels == nil {
+			c.Levels = make(map[string]zapcore.Level)
+		}
+		c.Levels[name] = level
+	}
+}
+
+// WithEncoding sets the Encoding configuration option.
+func WithEncoding(encoding Encoding) Option {
+	return func(c *Config) {
+		c.Encoding = encoding
+	}
+}
+
+// WithTimeLayout sets the TimeLayout configuration option.
+func WithTimeLayout(layout string) Option {
+	return func(c *Config) {
+		c.TimeLayout = layout
+	}
+}
+
+// WithColor sets the Color configuration option.
+func WithColor(enabled bool) Option {
+	return func(c *Config) {
+		c.Color = &enabled
+	}
+}
+
+// WithCaller sets the DisableCaller configuration option to the negation of
+// enabled.
+func WithCaller(enabled bool) Option {
+	return func(c *Config) {
+		c.DisableCaller = !enabled
+	}
+}
+
+// WithStacktrace sets the Stacktrace configuration option.
+func WithStacktrace(level zapcore.Level) Option {
+	return func(c *Config) {
+		c.Stacktrace = &level
+	}
+}
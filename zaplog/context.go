@@ -0,0 +1,45 @@
+package zaplog
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// contextKey is the context key for the logger.
+type contextKey struct{}
+
+// defaultLogger is the logger returned by FromContext if the context does not
+// carry a logger. If nil, a no-op logger is used.
+var defaultLogger atomic.Pointer[zap.Logger]
+
+// SetDefault sets the logger that FromContext returns if the context does not
+// carry a logger. Passing nil restores the default behavior of returning a
+// no-op logger. It is safe to call SetDefault concurrently with FromContext.
+func SetDefault(log *zap.Logger) {
+	defaultLogger.Store(log)
+}
+
+// WithContext returns a copy of ctx that carries the given logger.
+func WithContext(ctx context.Context, log *zap.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, log)
+}
+
+// FromContext returns the logger carried by ctx. If there is none, it returns
+// the logger set by SetDefault or a no-op logger.
+func FromContext(ctx context.Context) *zap.Logger {
+	if log, ok := ctx.Value(contextKey{}).(*zap.Logger); ok && log != nil {
+		return log
+	}
+	if log := defaultLogger.Load(); log != nil {
+		return log
+	}
+	return zap.NewNop()
+}
+
+// With returns a copy of ctx that carries a child of the ctx’s logger (see
+// FromContext) with the given fields added.
+func With(ctx context.Context, fields ...zap.Field) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(fields...))
+}
@@ -0,0 +1,40 @@
+package zaplog_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"go.pact.im/x/zaplog"
+)
+
+func TestFromContext(t *testing.T) {
+	ctx := context.Background()
+	if log := zaplog.FromContext(ctx); log.Core().Enabled(zap.FatalLevel) {
+		t.Fatal("expected no-op logger")
+	}
+
+	var def bytes.Buffer
+	zaplog.SetDefault(zaplog.New(&def, zaplog.WithCaller(false)))
+	defer zaplog.SetDefault(nil)
+	zaplog.FromContext(zaplog.With(ctx, zap.Int("n", 1))).Info("default")
+
+	var buf bytes.Buffer
+	ctx = zaplog.WithContext(ctx, zaplog.New(&buf, zaplog.WithCaller(false)))
+	ctx = zaplog.With(ctx, zap.Int("n", 2))
+	zaplog.FromContext(zaplog.With(ctx, zap.Int("m", 3))).Info("request")
+	zaplog.FromContext(ctx).Info("parent")
+
+	if got, want := def.String(), "\tinfo\tdefault\t{\"n\": 1}\n"; !strings.HasSuffix(got, want) {
+		t.Fatalf("unexpected output: %q", got)
+	}
+	if got, want := buf.String(), "\tinfo\trequest\t{\"n\": 2, \"m\": 3}\n"; !strings.Contains(got, want) {
+		t.Fatalf("unexpected output: %q", got)
+	}
+	if got, want := buf.String(), "\tinfo\tparent\t{\"n\": 2}\n"; !strings.HasSuffix(got, want) {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
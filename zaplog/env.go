@@ -0,0 +1,130 @@
+package zaplog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Environment variables that are used by EnvOptions.
+const (
+	// EnvLevel is the environment variable for the Level configuration
+	// option.
+	EnvLevel = "LOG_LEVEL"
+	// EnvFormat is the environment variable for the Encoding configuration
+	// option.
+	EnvFormat = "LOG_FORMAT"
+	// EnvCaller is the environment variable for the DisableCaller
+	// configuration option. It accepts boolean values recognized by
+	// strconv.ParseBool and disables caller annotations if false.
+	EnvCaller = "LOG_CALLER"
+	// EnvColor is the environment variable for the Color configuration
+	// option. It accepts boolean values recognized by strconv.ParseBool.
+	EnvColor = "LOG_COLOR"
+	// EnvNoColor is the environment variable that disables colors if set
+	// to a non-empty value (see https://no-color.org). It takes precedence
+	// over LOG_COLOR.
+	EnvNoColor = "NO_COLOR"
+	// EnvLevelPrefix is the prefix of the environment variables for the
+	// per-logger level overrides. For example, LOG_LEVEL_http=debug sets
+	// debug level for the logger named “http”.
+	EnvLevelPrefix = EnvLevel + "_"
+)
+
+// FromEnv returns a new zap.Logger that writes to w and uses configuration
+// options from the environment variables. See EnvOptions.
+func FromEnv(w io.Writer) (*zap.Logger, error) {
+	opts, err := EnvOptions()
+	if err != nil {
+		return nil, err
+	}
+	return New(w, opts...), nil
+}
+
+// EnvOptions returns configuration options from the LOG_LEVEL, LOG_FORMAT,
+// LOG_CALLER, LOG_COLOR, NO_COLOR and LOG_LEVEL_<name> environment variables.
+// Empty variables are ignored. It returns an error if any variable has an
+// invalid value.
+func EnvOptions() ([]Option, error) {
+	return envOptions(os.Environ())
+}
+
+// envOptions returns configuration options from the given environment in the
+// form of “key=value” strings.
+func envOptions(environ []string) ([]Option, error) {
+	var opts []Option
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		if value == "" {
+			continue
+		}
+		opt, err := envOption(key, value)
+		if err != nil {
+			return nil, fmt.Errorf("zaplog: invalid %s value: %w", key, err)
+		}
+		if opt != nil {
+			opts = append(opts, opt)
+		}
+	}
+	if slices.ContainsFunc(environ, func(kv string) bool {
+		key, value, _ := strings.Cut(kv, "=")
+		return key == EnvNoColor && value != ""
+	}) {
+		opts = append(opts, WithColor(false))
+	}
+	return opts, nil
+}
+
+// envOption returns configuration option for the given environment variable.
+// It returns nil option if the variable is not used for configuration.
+func envOption(key, value string) (Option, error) {
+	switch key {
+	case EnvLevel:
+		lvl, err := parseLevel(value)
+		if err != nil {
+			return nil, err
+		}
+		return WithLevel(lvl), nil
+	case EnvFormat:
+		enc, err := parseEncoding(value)
+		if err != nil {
+			return nil, err
+		}
+		return WithEncoding(enc), nil
+	case EnvCaller:
+		enabled, err := parseBool(value)
+		if err != nil {
+			return nil, err
+		}
+		return WithCaller(enabled), nil
+	case EnvColor:
+		enabled, err := parseBool(value)
+		if err != nil {
+			return nil, err
+		}
+		return WithColor(enabled), nil
+	}
+	name, ok := strings.CutPrefix(key, EnvLevelPrefix)
+	if !ok || name == "" {
+		return nil, nil
+	}
+	lvl, err := parseLevel(value)
+	if err != nil {
+		return nil, err
+	}
+	return WithNamedLevel(name, lvl), nil
+}
+
+// parseBool parses the boolean value.
+func parseBool(s string) (bool, error) {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("unknown boolean %q (accepted values: true, false, 1, 0)", s)
+	}
+	return v, nil
+}
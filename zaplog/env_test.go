@@ -0,0 +1,90 @@
+package zaplog_test
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"go.pact.im/x/zaplog"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_FORMAT", "json")
+	t.Setenv("LOG_CALLER", "false")
+	t.Setenv("LOG_LEVEL_http", "debug")
+
+	var buf bytes.Buffer
+	log, err := zaplog.FromEnv(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logAll(log)
+	logAll(log.Named("http"))
+
+	const want = `{"level":"error","time":"2022-02-24T04:00:00.000Z","logger":"test","msg":"error","n":42}
+{"level":"debug","time":"2022-02-24T04:00:00.000Z","logger":"http.test","msg":"debug","elapsed":"1s"}
+{"level":"info","time":"2022-02-24T04:00:00.000Z","logger":"http.test","msg":"info","key":"value"}
+{"level":"error","time":"2022-02-24T04:00:00.000Z","logger":"http.test","msg":"error","n":42}
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFromEnvInvalid(t *testing.T) {
+	testCases := []struct {
+		key, value string
+		accepted   string
+	}{
+		{"LOG_LEVEL", "verbose", "debug, info, warn, error, dpanic, panic, fatal"},
+		{"LOG_FORMAT", "xml", "console, json"},
+		{"LOG_CALLER", "maybe", "true, false"},
+		{"LOG_LEVEL_http", "trace", "debug, info"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			t.Setenv(tc.key, tc.value)
+			_, err := zaplog.FromEnv(io.Discard)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if msg := err.Error(); !strings.Contains(msg, tc.key) || !strings.Contains(msg, tc.accepted) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	opts := zaplog.Flags(fs)
+	if err := fs.Parse([]string{"-log-level=error", "-log-format=json"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logAll(zaplog.New(&buf, append(opts(), zaplog.WithCaller(false))...))
+
+	const want = `{"level":"error","time":"2022-02-24T04:00:00.000Z","logger":"test","msg":"error","n":42}
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFlagsInvalid(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	opts := zaplog.Flags(fs)
+	err := fs.Parse([]string{"-log-level=verbose"})
+	if err == nil || !strings.Contains(err.Error(), "accepted values") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opts()) != 0 {
+		t.Fatal("expected no options")
+	}
+}
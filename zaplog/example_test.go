@@ -2,7 +2,10 @@ package zaplog_test
 
 import (
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -28,3 +31,35 @@ func ExampleTee() {
 	lg.Debug("cheeki breeki")
 	// Output: debug: cheeki breeki: {"shark": "gawr gura"}
 }
+
+func ExampleNew() {
+	lg := zaplog.New(os.Stdout,
+		zaplog.WithLevel(zap.InfoLevel),
+		zaplog.WithEncoding(zaplog.EncodingJSON),
+		zaplog.WithTimeLayout(time.RFC1123),
+		zaplog.WithCaller(false),
+	).WithOptions(zap.WithClock(fixedClock{}))
+	lg.Debug("ignored")
+	lg.Info("cheeki breeki", zap.String("shark", "gawr gura"))
+	// Output: {"level":"info","time":"Thu, 24 Feb 2022 04:00:00 UTC","msg":"cheeki breeki","shark":"gawr gura"}
+}
+
+func ExampleLevelHandler() {
+	lg := zaplog.New(os.Stdout,
+		zaplog.WithLevel(zap.InfoLevel),
+		zaplog.WithCaller(false),
+	).WithOptions(zap.WithClock(fixedClock{}))
+
+	mux := http.NewServeMux()
+	mux.Handle("/log/level", zaplog.LevelHandler(lg))
+
+	lg.Named("http").Debug("ignored")
+
+	req := httptest.NewRequest(http.MethodPut, "/log/level?logger=http&level=debug", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	lg.Named("app").Debug("ignored")
+	lg.Named("http").Debug("cheeki breeki")
+	// Output: 2022-02-24T04:00:00.000Z	debug	http	cheeki breeki
+}
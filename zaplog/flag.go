@@ -0,0 +1,37 @@
+package zaplog
+
+import (
+	"flag"
+)
+
+// Flags registers -log-level and -log-format command-line flags in fs. It
+// returns a function that returns configuration options for the flags that
+// were set. Invalid flag values are reported by fs.Parse.
+func Flags(fs *flag.FlagSet) func() []Option {
+	var level, encoding Option
+	fs.Func("log-level", "minimum enabled logging level", func(s string) error {
+		lvl, err := parseLevel(s)
+		if err != nil {
+			return err
+		}
+		level = WithLevel(lvl)
+		return nil
+	})
+	fs.Func("log-format", "log entry encoding (console, json or auto)", func(s string) error {
+		enc, err := parseEncoding(s)
+		if err != nil {
+			return err
+		}
+		encoding = WithEncoding(enc)
+		return nil
+	})
+	return func() []Option {
+		var opts []Option
+		for _, o := range []Option{level, encoding} {
+			if o != nil {
+				opts = append(opts, o)
+			}
+		}
+		return opts
+	}
+}
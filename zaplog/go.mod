@@ -2,7 +2,12 @@ module go.pact.im/x/zaplog
 
 go 1.24.0
 
-require go.uber.org/zap v1.24.0
+require (
+	go.uber.org/multierr v1.9.0
+	go.uber.org/zap v1.24.0
+	golang.org/x/sys v0.21.0
+	golang.org/x/term v0.21.0
+)
 
 require (
 	github.com/benbjohnson/clock v1.3.0 // indirect
@@ -10,5 +15,4 @@ require (
 	github.com/stretchr/testify v1.9.0 // indirect
 	go.uber.org/atomic v1.10.0 // indirect
 	go.uber.org/goleak v1.3.0 // indirect
-	go.uber.org/multierr v1.9.0 // indirect
 )
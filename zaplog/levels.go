@@ -0,0 +1,247 @@
+package zaplog
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Levels is a registry of logging levels. It holds the base level and
+// per-logger overrides keyed by the logger name (as built by zap.Logger’s
+// Named method). An override for a name also applies to the loggers named
+// under it, e.g. an override for "http" applies to "http.server" unless there
+// is a more specific override.
+//
+// Levels is safe for concurrent use.
+type Levels struct {
+	base zap.AtomicLevel
+
+	// mu serializes overrides updates.
+	mu sync.Mutex
+	// overrides is a copy-on-write map of per-logger levels.
+	overrides atomic.Pointer[map[string]zap.AtomicLevel]
+}
+
+// NewLevels returns a new Levels registry with the given base level and
+// per-logger overrides.
+func NewLevels(base zapcore.Level, overrides map[string]zapcore.Level) *Levels {
+	l := &Levels{base: zap.NewAtomicLevelAt(base)}
+	m := make(map[string]zap.AtomicLevel, len(overrides))
+	for name, lvl := range overrides {
+		m[name] = zap.NewAtomicLevelAt(lvl)
+	}
+	l.overrides.Store(&m)
+	return l
+}
+
+// LevelsOf returns the Levels registry of a logger created by this package.
+// For loggers with multiple sinks, it returns the registry of the primary sink.
+// It returns false if the logger’s core was replaced, e.g. using Tee.
+func LevelsOf(log *zap.Logger) (*Levels, bool) {
+	c, ok := log.Core().(*rootCore)
+	if !ok {
+		return nil, false
+	}
+	return c.levels, true
+}
+
+// Level returns the base level that is used for loggers without overrides.
+func (l *Levels) Level() zap.AtomicLevel {
+	return l.base
+}
+
+// Named returns the level override for the given logger name, if any.
+func (l *Levels) Named(name string) (zap.AtomicLevel, bool) {
+	lvl, ok := (*l.overrides.Load())[name]
+	return lvl, ok
+}
+
+// Overrides returns a snapshot of per-logger level overrides.
+func (l *Levels) Overrides() map[string]zapcore.Level {
+	m := *l.overrides.Load()
+	s := make(map[string]zapcore.Level, len(m))
+	for name, lvl := range m {
+		s[name] = lvl.Level()
+	}
+	return s
+}
+
+// SetNamed sets the level override for the given logger name.
+func (l *Levels) SetNamed(name string, lvl zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if a, ok := l.Named(name); ok {
+		a.SetLevel(lvl)
+		return
+	}
+	m := maps.Clone(*l.overrides.Load())
+	m[name] = zap.NewAtomicLevelAt(lvl)
+	l.overrides.Store(&m)
+}
+
+// UnsetNamed removes the level override for the given logger name.
+func (l *Levels) UnsetNamed(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.Named(name); !ok {
+		return
+	}
+	m := maps.Clone(*l.overrides.Load())
+	delete(m, name)
+	l.overrides.Store(&m)
+}
+
+// LevelOf returns the effective level for the given logger name.
+func (l *Levels) LevelOf(name string) zapcore.Level {
+	m := *l.overrides.Load()
+	for len(m) != 0 {
+		if lvl, ok := m[name]; ok {
+			return lvl.Level()
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return l.base.Level()
+}
+
+// Enabled implements the zapcore.LevelEnabler interface. It returns true if
+// the given level is enabled for at least one logger name.
+func (l *Levels) Enabled(lvl zapcore.Level) bool {
+	if l.base.Enabled(lvl) {
+		return true
+	}
+	for _, a := range *l.overrides.Load() {
+		if a.Enabled(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+// levelCore is a zapcore.Core that filters entries using the level of the
+// entry’s logger name.
+type levelCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+// Enabled implements the zapcore.Core interface.
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return c.levels.Enabled(lvl)
+}
+
+// With implements the zapcore.Core interface.
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{
+		Core:   c.Core.With(fields),
+		levels: c.levels,
+	}
+}
+
+// Check implements the zapcore.Core interface.
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.LevelOf(ent.LoggerName).Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// allLevels is a zapcore.LevelEnabler that enables all levels.
+var allLevels = zap.LevelEnablerFunc(func(zapcore.Level) bool {
+	return true
+})
+
+// LevelHandler returns an http.Handler that reports and changes logging levels
+// of the given logger. It follows the zap.AtomicLevel’s GET/PUT protocol for
+// the base level. If the “logger” query parameter is set, requests apply to
+// the level override for that logger name instead, and DELETE method removes
+// the override.
+//
+// Example requests:
+//
+//	curl localhost:8080/log/level
+//	curl -X PUT localhost:8080/log/level?level=info
+//	curl -X PUT localhost:8080/log/level?logger=http -d level=debug
+//	curl -X DELETE localhost:8080/log/level?logger=http
+//
+// The handler responds with an error if the logger was not created by this
+// package.
+func LevelHandler(log *zap.Logger) http.Handler {
+	levels, ok := LevelsOf(log)
+	if !ok {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			writeError(w, http.StatusNotImplemented, "logger does not support level control")
+		})
+	}
+	return &levelHandler{levels: levels}
+}
+
+// levelHandler is an http.Handler that serves requests for the Levels.
+type levelHandler struct {
+	levels *Levels
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("logger")
+	if name == "" {
+		h.levels.Level().ServeHTTP(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeLevel(w, h.levels.LevelOf(name))
+	case http.MethodPut:
+		// Apply the request to a temporary level first so that malformed
+		// requests do not register an override.
+		lvl := zap.NewAtomicLevelAt(h.levels.LevelOf(name))
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		lvl.ServeHTTP(sw, r)
+		if sw.status == http.StatusOK {
+			h.levels.SetNamed(name, lvl.Level())
+		}
+	case http.MethodDelete:
+		h.levels.UnsetNamed(name)
+		writeLevel(w, h.levels.LevelOf(name))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Only GET, PUT and DELETE are supported.")
+	}
+}
+
+// writeLevel writes JSON level response in the format used by zap.AtomicLevel.
+func writeLevel(w http.ResponseWriter, lvl zapcore.Level) {
+	_ = json.NewEncoder(w).Encode(struct {
+		Level zapcore.Level `json:"level"`
+	}{lvl})
+}
+
+// writeError writes JSON error response in the format used by zap.AtomicLevel.
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{msg})
+}
+
+// statusWriter is an http.ResponseWriter that records the response status
+// code.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements the http.ResponseWriter interface.
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
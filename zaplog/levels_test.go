@@ -0,0 +1,100 @@
+package zaplog_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"go.pact.im/x/zaplog"
+)
+
+func TestLevelsNamed(t *testing.T) {
+	var buf bytes.Buffer
+	log := zaplog.New(&buf,
+		zaplog.WithLevel(zap.InfoLevel),
+		zaplog.WithNamedLevel("http", zap.DebugLevel),
+		zaplog.WithCaller(false),
+	).WithOptions(zap.WithClock(fixedClock{}))
+
+	log.Named("app").Debug("app")
+	log.Named("http").Named("server").Debug("http.server")
+	log.Named("httpx").Debug("httpx")
+	log.Debug("root")
+
+	levels, ok := zaplog.LevelsOf(log.With(zap.Int("n", 42)))
+	if !ok {
+		t.Fatal("expected logger to have levels")
+	}
+	levels.SetNamed("app", zap.DebugLevel)
+	levels.UnsetNamed("http")
+	levels.Level().SetLevel(zap.ErrorLevel)
+
+	log.Named("app").Named("db").Debug("app.db")
+	log.Named("http").Info("http")
+	log.Error("root")
+
+	const want = "2022-02-24T04:00:00.000Z\tdebug\thttp.server\thttp.server\n" +
+		"2022-02-24T04:00:00.000Z\tdebug\tapp.db\tapp.db\n" +
+		"2022-02-24T04:00:00.000Z\terror\troot\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	log := zaplog.New(nil, zaplog.WithLevel(zap.InfoLevel))
+	h := zaplog.LevelHandler(log)
+
+	serve := func(method, target, body string) (int, string) {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code, w.Body.String()
+	}
+
+	testCases := []struct {
+		method string
+		target string
+		body   string
+		code   int
+		resp   string
+	}{
+		{http.MethodGet, "/", "", http.StatusOK, `{"level":"info"}`},
+		{http.MethodGet, "/?logger=http", "", http.StatusOK, `{"level":"info"}`},
+		{http.MethodPut, "/?logger=http", "level=oops", http.StatusBadRequest, ""},
+		{http.MethodPut, "/?logger=http", "level=debug", http.StatusOK, `{"level":"debug"}`},
+		{http.MethodPut, "/", "level=warn", http.StatusOK, `{"level":"warn"}`},
+		{http.MethodGet, "/?logger=http.server", "", http.StatusOK, `{"level":"debug"}`},
+		{http.MethodDelete, "/?logger=http", "", http.StatusOK, `{"level":"warn"}`},
+		{http.MethodPost, "/?logger=http", "", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tc := range testCases {
+		code, resp := serve(tc.method, tc.target, tc.body)
+		if code != tc.code {
+			t.Fatalf("%s %s: unexpected status code %d", tc.method, tc.target, code)
+		}
+		if tc.resp != "" && strings.TrimSpace(resp) != tc.resp {
+			t.Fatalf("%s %s: unexpected response %s", tc.method, tc.target, resp)
+		}
+	}
+
+	levels, _ := zaplog.LevelsOf(log)
+	if overrides := levels.Overrides(); len(overrides) != 0 {
+		t.Fatalf("unexpected overrides: %v", overrides)
+	}
+}
+
+func TestLevelHandlerUnsupported(t *testing.T) {
+	w := httptest.NewRecorder()
+	zaplog.LevelHandler(zap.NewNop()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("unexpected status code %d", w.Code)
+	}
+}
@@ -0,0 +1,106 @@
+package zaplog
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Rotator is a log destination that supports rotation. It can be used as a
+// sink writer. Rotation may either reopen the file at the same path after it
+// was moved by an external tool (see File) or perform rotation itself, e.g.
+// size-based rotation implemented by a third-party library.
+type Rotator interface {
+	io.WriteCloser
+	Rotate() error
+}
+
+// File is a log file that is reopened on rotation. It is intended for use with
+// external log rotation tools such as logrotate that move the file and then
+// notify the process, e.g. using SIGHUP signal.
+//
+// File is safe for concurrent use.
+type File struct {
+	path string
+
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenFile opens the log file at the given path for appending. The file is
+// created if it does not exist.
+func OpenFile(path string) (*File, error) {
+	f, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &File{path: path, f: f}, nil
+}
+
+// openFile opens the file at the given path for appending.
+func openFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+}
+
+// Write implements the io.Writer interface.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.Write(p)
+}
+
+// Sync implements the zapcore.WriteSyncer interface.
+func (f *File) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.Sync()
+}
+
+// Close implements the io.Closer interface.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.Close()
+}
+
+// Rotate implements the Rotator interface. It reopens the file at the same
+// path. If the file cannot be opened, the current file remains in use.
+func (f *File) Rotate() error {
+	nf, err := openFile(f.path)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	old := f.f
+	f.f = nf
+	return old.Close()
+}
+
+// RotateOnSignal rotates r each time the process receives one of the given
+// signals until the context is canceled. If no signals are provided, it
+// defaults to SIGHUP. Rotation errors are passed to onError, if it is not nil,
+// and do not stop RotateOnSignal from handling subsequent signals.
+func RotateOnSignal(ctx context.Context, r Rotator, onError func(error), sig ...os.Signal) {
+	if len(sig) == 0 {
+		sig = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig...)
+	defer signal.Stop(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			if err := r.Rotate(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
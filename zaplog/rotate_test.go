@@ -0,0 +1,124 @@
+package zaplog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.pact.im/x/zaplog"
+)
+
+func TestFileRotate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := zaplog.OpenFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	log := zaplog.New(f, zaplog.WithCaller(false))
+
+	log.Info("before")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := f.Rotate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	log.Info("after")
+
+	if err := zaplog.Close(log); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertContains(t, path+".1", "before")
+	assertContains(t, path, "after")
+}
+
+// rotateNotifier is a Rotator that reports rotations and fails the first
+// rotation with the given error.
+type rotateNotifier struct {
+	zaplog.Rotator
+	rotated chan struct{}
+	err     error
+}
+
+func (r *rotateNotifier) Rotate() error {
+	if err := r.err; err != nil {
+		r.err = nil
+		return err
+	}
+	select {
+	case r.rotated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func TestRotateOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP is not supported on Windows")
+	}
+
+	// Prevent SIGHUP from terminating the test if it is delivered before
+	// RotateOnSignal registers the handler.
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	defer signal.Stop(ch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	oops := errors.New("oops")
+	r := &rotateNotifier{rotated: make(chan struct{}, 1), err: oops}
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		zaplog.RotateOnSignal(ctx, r, func(err error) {
+			errs <- err
+		})
+	}()
+
+	// The first rotation fails, and RotateOnSignal keeps handling signals.
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for rotated := false; !rotated; {
+		select {
+		case <-ticker.C:
+			_ = proc.Signal(syscall.SIGHUP)
+		case <-r.rotated:
+			rotated = true
+		}
+	}
+
+	cancel()
+	<-done
+
+	if err := <-errs; !errors.Is(err, oops) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func assertContains(t *testing.T, path, msg string) {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(b, []byte(msg)) {
+		t.Fatalf("expected %s to contain %q: %s", path, msg, b)
+	}
+}
@@ -0,0 +1,78 @@
+package zaplog
+
+import (
+	"io"
+	"os"
+	"slices"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sinkConfig is the configuration for the additional log destination.
+type sinkConfig struct {
+	w    io.Writer
+	opts []Option
+}
+
+// WithSink adds a log destination that writes to w in addition to the primary
+// writer. The sink inherits the configuration of the logger with the given
+// options applied, so it may use its own level and encoding. Note that caller
+// and stack trace capture is controlled by the logger’s configuration.
+//
+// Each sink has its own level registry that is not affected by changes to the
+// levels of the primary writer (see LevelsOf).
+func WithSink(w io.Writer, opts ...Option) Option {
+	return func(c *Config) {
+		c.sinks = append(slices.Clip(c.sinks), sinkConfig{w: w, opts: opts})
+	}
+}
+
+// rootCore is the zapcore.Core of the loggers created by this package. It
+// writes entries to all sinks.
+type rootCore struct {
+	zapcore.Core
+	// levels is the level registry of the primary sink.
+	levels *Levels
+	// writers is a list of sink writers.
+	writers []io.Writer
+}
+
+// With implements the zapcore.Core interface.
+func (c *rootCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rootCore{
+		Core:    c.Core.With(fields),
+		levels:  c.levels,
+		writers: c.writers,
+	}
+}
+
+// Close flushes buffered log entries and closes all sink writers of a logger
+// created by this package. Writers that implement Sync method are synced, and
+// writers that implement io.Closer are closed, except for os.Stdout and
+// os.Stderr that are never closed and whose sync errors are ignored (syncing
+// fails if they refer to a pipe or terminal). It returns the combined error for
+// all sinks. For other loggers, it is equivalent to log.Sync.
+func Close(log *zap.Logger) error {
+	c, ok := log.Core().(*rootCore)
+	if !ok {
+		return log.Sync()
+	}
+	var errs []error
+	for _, w := range c.writers {
+		std := w == os.Stdout || w == os.Stderr
+		if s, ok := w.(interface{ Sync() error }); ok {
+			if err := s.Sync(); err != nil && !std {
+				errs = append(errs, err)
+			}
+		}
+		if std {
+			continue
+		}
+		if closer, ok := w.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return multierr.Combine(errs...)
+}
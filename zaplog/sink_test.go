@@ -0,0 +1,97 @@
+package zaplog_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"go.pact.im/x/zaplog"
+)
+
+// closeWriter is an io.WriteCloser that returns the given errors on Sync and
+// Close.
+type closeWriter struct {
+	bytes.Buffer
+	err     error
+	syncErr error
+	closed  bool
+	synced  bool
+}
+
+func (w *closeWriter) Sync() error {
+	w.synced = true
+	return w.syncErr
+}
+
+func (w *closeWriter) Close() error {
+	w.closed = true
+	return w.err
+}
+
+func TestWithSink(t *testing.T) {
+	var stdout, debug bytes.Buffer
+	log := zaplog.New(&stdout,
+		zaplog.WithLevel(zap.InfoLevel),
+		zaplog.WithCaller(false),
+		zaplog.WithSink(&debug,
+			zaplog.WithLevel(zap.DebugLevel),
+			zaplog.WithEncoding(zaplog.EncodingJSON),
+		),
+	)
+	logAll(log)
+
+	const wantStdout = "2022-02-24T04:00:00.000Z\tinfo\ttest\tinfo\t{\"key\": \"value\"}\n" +
+		"2022-02-24T04:00:00.000Z\terror\ttest\terror\t{\"n\": 42}\n"
+	if got := stdout.String(); got != wantStdout {
+		t.Fatalf("unexpected output:\n%q\nwant:\n%q", got, wantStdout)
+	}
+
+	const wantDebug = `{"level":"debug","time":"2022-02-24T04:00:00.000Z","logger":"test","msg":"debug","elapsed":"1s"}
+{"level":"info","time":"2022-02-24T04:00:00.000Z","logger":"test","msg":"info","key":"value"}
+{"level":"error","time":"2022-02-24T04:00:00.000Z","logger":"test","msg":"error","n":42}
+`
+	if got := debug.String(); got != wantDebug {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, wantDebug)
+	}
+}
+
+func TestClose(t *testing.T) {
+	errA, errB, errC := errors.New("a"), errors.New("b"), errors.New("c")
+	a := &closeWriter{err: errA}
+	b := &closeWriter{err: errB}
+	c := &closeWriter{syncErr: errC}
+
+	log := zaplog.New(a, zaplog.WithSink(b), zaplog.WithSink(c)).With(zap.Int("n", 42))
+	err := zaplog.Close(log)
+	if !errors.Is(err, errA) || !errors.Is(err, errB) || !errors.Is(err, errC) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, w := range []*closeWriter{a, b, c} {
+		if !w.synced || !w.closed {
+			t.Fatal("expected all sinks to be synced and closed")
+		}
+	}
+}
+
+func TestCloseStderrPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+	defer func() { _ = w.Close() }()
+
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	if err := zaplog.Close(zaplog.New(os.Stderr)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.Write(nil); err != nil {
+		t.Fatalf("expected stderr to remain open: %v", err)
+	}
+}
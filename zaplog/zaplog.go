@@ -8,23 +8,53 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-// New returns a new zap.Logger that writes to w.
-func New(w io.Writer) *zap.Logger {
-	return zap.New(zapcore.NewCore(
-		zapcore.NewConsoleEncoder(zapcore.EncoderConfig{
-			MessageKey:     "msg",
-			LevelKey:       "level",
-			TimeKey:        "time",
-			NameKey:        "logger",
-			CallerKey:      "caller",
-			EncodeLevel:    zapcore.LowercaseLevelEncoder,
-			EncodeTime:     zapcore.ISO8601TimeEncoder,
-			EncodeDuration: zapcore.StringDurationEncoder,
-			EncodeCaller:   zapcore.FullCallerEncoder,
-		}),
-		zapcore.AddSync(w),
-		zap.DebugLevel,
-	), zap.WithCaller(true))
+// New returns a new zap.Logger that writes to w. It uses default
+// configuration with the given options applied.
+func New(w io.Writer, opts ...Option) *zap.Logger {
+	c := defaultConfig
+	for _, o := range opts {
+		o(&c)
+	}
+	return newWithConfig(w, c)
+}
+
+// NewWithConfig returns a new zap.Logger that writes to w and uses the given
+// configuration. If c is nil, default values are used.
+func NewWithConfig(w io.Writer, c *Config) *zap.Logger {
+	cc := defaultConfig
+	if c != nil {
+		cc = *c
+	}
+	return newWithConfig(w, cc)
+}
+
+// newWithConfig returns a new zap.Logger for the given configuration.
+func newWithConfig(w io.Writer, c Config) *zap.Logger {
+	c.setDefaults()
+	opts := []zap.Option{zap.WithCaller(!c.DisableCaller)}
+	if c.Stacktrace != nil {
+		opts = append(opts, zap.AddStacktrace(*c.Stacktrace))
+	}
+
+	levels := NewLevels(c.level(), c.Levels)
+	cores := []zapcore.Core{c.core(w, levels)}
+	writers := []io.Writer{w}
+	for _, s := range c.sinks {
+		sc := c
+		sc.sinks = nil
+		for _, o := range s.opts {
+			o(&sc)
+		}
+		sc.setDefaults()
+		cores = append(cores, sc.core(s.w, NewLevels(sc.level(), sc.Levels)))
+		writers = append(writers, s.w)
+	}
+
+	return zap.New(&rootCore{
+		Core:    zapcore.NewTee(cores...),
+		levels:  levels,
+		writers: writers,
+	}, opts...)
 }
 
 // Tee returns log’s clone that duplicates log entries into another core.
@@ -0,0 +1,99 @@
+package zaplog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"go.pact.im/x/zaplog"
+)
+
+// fixedClock is a zapcore.Clock that always returns the same time.
+type fixedClock struct{}
+
+func (fixedClock) Now() time.Time {
+	return time.Date(2022, time.February, 24, 4, 0, 0, 0, time.UTC)
+}
+
+func (fixedClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
+
+// legacyNew is the zaplog.New implementation before configuration options
+// were introduced.
+func legacyNew(w io.Writer) *zap.Logger {
+	return zap.New(zapcore.NewCore(
+		zapcore.NewConsoleEncoder(zapcore.EncoderConfig{
+			MessageKey:     "msg",
+			LevelKey:       "level",
+			TimeKey:        "time",
+			NameKey:        "logger",
+			CallerKey:      "caller",
+			EncodeLevel:    zapcore.LowercaseLevelEncoder,
+			EncodeTime:     zapcore.ISO8601TimeEncoder,
+			EncodeDuration: zapcore.StringDurationEncoder,
+			EncodeCaller:   zapcore.FullCallerEncoder,
+		}),
+		zapcore.AddSync(w),
+		zap.DebugLevel,
+	), zap.WithCaller(true))
+}
+
+func logAll(log *zap.Logger) {
+	log = log.WithOptions(zap.WithClock(fixedClock{})).Named("test")
+	log.Debug("debug", zap.Duration("elapsed", time.Second))
+	log.Info("info", zap.String("key", "value"))
+	log.Error("error", zap.Int("n", 42))
+}
+
+func TestNewDefaultsCompatible(t *testing.T) {
+	var got, want bytes.Buffer
+	logAll(zaplog.New(&got))
+	logAll(legacyNew(&want))
+	if got.String() != want.String() {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got.String(), want.String())
+	}
+
+	got.Reset()
+	logAll(zaplog.NewWithConfig(&got, nil))
+	if got.String() != want.String() {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got.String(), want.String())
+	}
+}
+
+func TestNewWithConfig(t *testing.T) {
+	var c zaplog.Config
+	err := json.Unmarshal([]byte(`{
+		"level": "info",
+		"encoding": "json",
+		"timeLayout": "epoch",
+		"disableCaller": true
+	}`), &c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logAll(zaplog.NewWithConfig(&buf, &c))
+
+	const want = `{"level":"info","time":1645675200,"logger":"test","msg":"info","key":"value"}
+{"level":"error","time":1645675200,"logger":"test","msg":"error","n":42}
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestConfigUnknownEncoding(t *testing.T) {
+	var c zaplog.Config
+	err := json.Unmarshal([]byte(`{"encoding":"xml"}`), &c)
+	if err == nil || !strings.Contains(err.Error(), "console, json") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}